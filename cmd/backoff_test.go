@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBackoffFlags_Timeout deletes an instance that never gets canceled, so only --backoff-timeout ends the wait.
+func TestBackoffFlags_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodDelete && r.URL.Path == "/v1/process-instances/2251799813685251":
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"status":400,"message":"Process instances needs to be in one of the states [COMPLETED, CANCELED]"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/process-instances/2251799813685251/cancellation":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/process-instances/2251799813685251":
+			_, _ = io.WriteString(w, `{"processInstanceKey":"2251799813685251","processDefinitionId":"order-process","state":"ACTIVE","hasIncident":false,"tenantId":"<default>"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	config := fmt.Sprintf(`auth:
+  mode: token
+  token:
+    value: test-token
+apis:
+  camunda_api:
+    base_url: %s/v2
+  operate_api:
+    base_url: %s
+`, srv.URL, srv.URL)
+
+	start := time.Now()
+	out, err := runWithConfig(t, config, "-a", "8.8", "delete", "pi", "--key", "2251799813685251", "--cancel",
+		"--backoff-timeout", "300ms", "--backoff-initial-delay", "20ms")
+	require.NoError(t, err)
+	require.Contains(t, out, "2251799813685251 failed: ")
+	require.Contains(t, out, "context deadline exceeded")
+	require.Less(t, time.Since(start), 5*time.Second, "the flag sets the timeout")
+}
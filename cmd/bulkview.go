@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/grafvonb/camunder/internal/services/common"
+	"github.com/spf13/cobra"
+)
+
+// bulkResultView prints one line per item and a final summary; it returns the number of failures.
+func bulkResultView[T any](cmd *cobra.Command, action string, results []common.Result[T], keyOf func(T) int64) int {
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			cmd.Println(fmt.Sprintf("%-16d failed: %v", keyOf(r.Item), r.Err))
+			continue
+		}
+		cmd.Println(fmt.Sprintf("%-16d ok", keyOf(r.Item)))
+	}
+	cmd.Println(fmt.Sprintf("%s: %d total, %d succeeded, %d failed", action, len(results), len(results)-failed, failed))
+	return failed
+}
+
+// countFailed returns the number of results with an error.
+func countFailed[T any](results []common.Result[T]) int {
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
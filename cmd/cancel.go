@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/grafvonb/camunder/internal/api/convert"
 	"github.com/grafvonb/camunder/internal/logging"
 	"github.com/grafvonb/camunder/internal/services/common"
 	"github.com/grafvonb/camunder/internal/services/processinstance"
+	piapi "github.com/grafvonb/camunder/pkg/camunda/processinstance"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -16,13 +19,13 @@ var supportedResourcesForCancel = common.ResourceTypes{
 }
 
 var (
-	flagCancelKey int64
+	flagCancelKeys []int64
 )
 
 // cancelCmd represents the cancel command
 var cancelCmd = &cobra.Command{
 	Use:     "cancel [resource name] [key]",
-	Short:   "Cancel a resource of a given type by its key. " + supportedResourcesForCancel.PrettyString(),
+	Short:   "Cancel resources of a given type by their keys or by search filter. " + supportedResourcesForCancel.PrettyString(),
 	Args:    cobra.ExactArgs(1),
 	Aliases: []string{"c", "cn", "stop", "abort"},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -41,11 +44,30 @@ var cancelCmd = &cobra.Command{
 				log.Error(fmt.Sprintf("creating process instance service: %v", err))
 				return
 			}
-			_, err = svc.CancelProcessInstance(cmd.Context(), flagCancelKey)
+			pis, err := selectProcessInstances(cmd, svc, flagCancelKeys)
 			if err != nil {
-				log.Error(fmt.Sprintf("cancelling process instance: %v", err))
+				log.Error(fmt.Sprintf("selecting process instances: %v", err))
 				return
 			}
+			if len(pis) == 0 {
+				log.Info("no process instances selected, nothing to cancel")
+				return
+			}
+			if flagDryRun {
+				planned := convert.MapSlice(resolveProcessInstanceStates(cmd, svc, pis), func(pi piapi.ProcessInstance) plannedAction {
+					return planCancel(pi)
+				})
+				dryRunView(cmd, "cancel", "process instance(s)", planned)
+				return
+			}
+			log.Debug(fmt.Sprintf("cancelling %d process instance(s)", len(pis)))
+			results := common.RunBulk(cmd.Context(), pis, flagParallel, func(ctx context.Context, pi piapi.ProcessInstance) error {
+				_, err := svc.CancelProcessInstance(ctx, pi.Key)
+				return err
+			})
+			if failed := bulkResultView(cmd, "cancel", results, processInstanceKey); failed > 0 {
+				log.Error(fmt.Sprintf("cancelling failed for %d of %d process instance(s)", failed, len(results)))
+			}
 		default:
 			log.Error(fmt.Sprintf("unknown resource type: %s, supported: %s", rn, supportedResourcesForCancel))
 		}
@@ -57,6 +79,6 @@ func init() {
 
 	AddBackoffFlagsAndBindings(cancelCmd, viper.GetViper())
 
-	cancelCmd.Flags().Int64VarP(&flagCancelKey, "key", "k", 0, "resource key (e.g. process instance) to cancel")
-	_ = cancelCmd.MarkFlagRequired("key")
+	cancelCmd.Flags().Int64SliceVarP(&flagCancelKeys, "key", "k", nil, "resource key (e.g. process instance) to cancel (repeatable or comma-separated)")
+	AddProcessInstanceSelectionFlags(cancelCmd)
 }
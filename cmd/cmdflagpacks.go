@@ -53,3 +53,29 @@ func requireAnyFlag(cmd *cobra.Command, flags ...string) error {
 	}
 	return fmt.Errorf("one of %v must be provided", flags)
 }
+
+// AddProcessInstanceSelectionFlags registers the search filter flags used to select process instances
+// and the --parallel flag for commands acting on many process instances at once.
+func AddProcessInstanceSelectionFlags(cmd *cobra.Command) {
+	fs := cmd.Flags()
+	fs.StringVarP(&flagBpmnProcessID, "bpmn-process-id", "b", "", "select process instances by BPMN process ID")
+	fs.Int32VarP(&flagProcessVersion, "process-version", "v", 0, "select process instances by process definition version")
+	fs.StringVarP(&flagState, "state", "s", "all", "select process instances by state: all, active, completed, canceled")
+	fs.Int64Var(&flagParentKey, "parent-key", 0, "select process instances by parent process instance key")
+	fs.BoolVar(&flagIncidentsOnly, "incidents-only", false, "select only process instances that have incidents")
+	fs.BoolVar(&flagOrphanParentsOnly, "orphan-parents-only", false, "select only child instances whose parent does not exist (return 404 on get by key)")
+
+	fs.IntVar(&flagParallel, "parallel", 0, "number of process instances processed in parallel (0 = min(8, number of instances))")
+}
+
+// AddIncidentSelectionFlags registers the search filter flags used to select incidents
+// and the --parallel flag for commands acting on many incidents at once.
+func AddIncidentSelectionFlags(cmd *cobra.Command) {
+	fs := cmd.Flags()
+	fs.StringVarP(&flagBpmnProcessID, "bpmn-process-id", "b", "", "select incidents by BPMN process ID")
+	fs.Int64Var(&flagProcessInstanceKey, "process-instance-key", 0, "select incidents by process instance key")
+	fs.StringVar(&flagErrorType, "error-type", "", "select incidents by error type, e.g. JOB_NO_RETRIES")
+	fs.Int64Var(&flagJobKey, "job-key", 0, "select incidents by job key")
+
+	fs.IntVar(&flagParallel, "parallel", 0, "number of incidents processed in parallel (0 = min(8, number of incidents))")
+}
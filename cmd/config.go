@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafvonb/camunder/internal/config"
+	"github.com/grafvonb/camunder/internal/logging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// exit codes of config validate
+const (
+	exitCodeInvalidConfig = 2
+)
+
+var errUnknownProfile = errors.New("unknown profile")
+
+var (
+	flagConfigInitMode           string
+	flagConfigInitFile           string
+	flagConfigInitForce          bool
+	flagConfigInitNoInput        bool
+	flagConfigInitCookieBaseURL  string
+	flagConfigInitCookieUsername string
+	flagConfigInitBasicUsername  string
+)
+
+// configCmd represents the config command group
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the camunder configuration, e.g. switch between named profiles",
+	Long: "Manage the camunder configuration. A config file can define named profiles (e.g. dev, staging, prod) under `profiles:`; " +
+		"the profile is selected by --profile, CAMUNDER_PROFILE or `current_profile` of the config file, in this order.",
+}
+
+var configUseProfileCmd = &cobra.Command{
+	Use:   "use-profile [name]",
+	Short: "Set the current_profile of the config file in use",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logging.FromContext(cmd.Context())
+		path, err := configFileInUse(cmd)
+		if err != nil {
+			log.Error(err.Error())
+			return
+		}
+		if err = config.SetCurrentProfile(path, args[0]); err != nil {
+			log.Error(fmt.Sprintf("switching profile: %v", err))
+			return
+		}
+		cmd.Println(fmt.Sprintf("switched to profile %q in %s", args[0], path))
+	},
+}
+
+var configListProfilesCmd = &cobra.Command{
+	Use:     "list-profiles",
+	Short:   "List the profiles of the config file in use, the effective one marked with *",
+	Aliases: []string{"profiles"},
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logging.FromContext(cmd.Context())
+		path, err := configFileInUse(cmd)
+		if err != nil {
+			log.Error(err.Error())
+			return
+		}
+		names, _, err := config.ReadProfiles(path)
+		if err != nil {
+			log.Error(fmt.Sprintf("reading profiles: %v", err))
+			return
+		}
+		cfg, _ := config.FromContext(cmd.Context())
+		for _, n := range names {
+			mark := " "
+			if cfg != nil && n == cfg.Profile {
+				mark = "*"
+			}
+			cmd.Println(fmt.Sprintf("%s %s", mark, n))
+		}
+	},
+}
+
+var configCurrentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Print the effective profile",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logging.FromContext(cmd.Context())
+		cfg, err := config.FromContext(cmd.Context())
+		if err != nil {
+			log.Error(err.Error())
+			return
+		}
+		if cfg.Profile == "" {
+			log.Info("no profile selected, the top-level settings of the config file are used")
+			return
+		}
+		cmd.Println(cfg.Profile)
+	},
+}
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print the effective configuration (secrets redacted) and the source of each value",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logging.FromContext(cmd.Context())
+		cfg, err := config.FromContext(cmd.Context())
+		if err != nil {
+			log.Error(err.Error())
+			return
+		}
+		state := configStateFromContext(cmd.Context())
+		if cfg.Config != "" {
+			cmd.Println("config loaded:", cfg.Config)
+		}
+		cmd.Println(cfg.String())
+		cmd.Println()
+		cmd.Println("sources:")
+		for _, key := range config.Keys() {
+			cmd.Println(fmt.Sprintf("  %-30s %s", key, configSource(cmd, state.v, cfg.Profile, key)))
+		}
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: fmt.Sprintf("Validate the effective configuration, exit code %d if it is invalid", exitCodeInvalidConfig),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.FromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		errs := []error{configStateFromContext(cmd.Context()).profileErr, cfg.CheckSecrets(), cfg.Validate()}
+		if err = errors.Join(errs...); err != nil {
+			cmd.Println("config is invalid:")
+			for _, line := range strings.Split(err.Error(), "\n") {
+				cmd.Println("  " + line)
+			}
+			return &exitError{code: exitCodeInvalidConfig, err: errors.New("invalid config")}
+		}
+		cmd.Println("config is valid")
+		return nil
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Generate a config file for oauth2, cookie, token or basic authentication",
+	Long: "Generate a config file. Values are taken from the flags (e.g. --mode, --camunda-base-url, --auth-token-url), " +
+		"missing ones are asked for when run in a terminal, unless --no-input is set; defaults are used otherwise. " +
+		"Secrets are not asked for, provide them as environment variables.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logging.FromContext(cmd.Context())
+		path := flagConfigInitFile
+		if path == "" {
+			var err error
+			if path, err = config.DefaultConfigPath(); err != nil {
+				log.Error(fmt.Sprintf("resolving config path: %v", err))
+				return
+			}
+		}
+		if _, err := os.Stat(path); err == nil && !flagConfigInitForce {
+			log.Error(fmt.Sprintf("%s already exists, use --force to overwrite it", path))
+			return
+		}
+		opts, err := configInitOptions(cmd)
+		if err != nil {
+			log.Error(err.Error())
+			return
+		}
+		data, err := config.RenderInitFile(opts)
+		if err != nil {
+			log.Error(fmt.Sprintf("rendering config file: %v", err))
+			return
+		}
+		if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			log.Error(fmt.Sprintf("creating config directory: %v", err))
+			return
+		}
+		if err = os.WriteFile(path, data, 0o600); err != nil {
+			log.Error(fmt.Sprintf("writing config file: %v", err))
+			return
+		}
+		cmd.Println("config written to", path)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key=value]...",
+	Short: "Set values in the config file in use, e.g. http.timeout=45s or profiles.prod.apis.version=8.8",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logging.FromContext(cmd.Context())
+		path, err := configFileInUse(cmd)
+		if err != nil {
+			log.Error(err.Error())
+			return
+		}
+		for _, arg := range args {
+			key, value, ok := strings.Cut(arg, "=")
+			if !ok || key == "" {
+				log.Error(fmt.Sprintf("invalid argument %q (expected key=value)", arg))
+				return
+			}
+			if !config.IsKnownKey(key) {
+				log.Error(fmt.Sprintf("unknown key %q, known keys: %s", key, strings.Join(config.Keys(), ", ")))
+				return
+			}
+			if err = config.SetFileValue(path, key, value); err != nil {
+				log.Error(fmt.Sprintf("setting %s: %v", key, err))
+				return
+			}
+			cmd.Println(fmt.Sprintf("%s set in %s", key, path))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configUseProfileCmd, configListProfilesCmd, configCurrentCmd,
+		configViewCmd, configValidateCmd, configInitCmd, configSetCmd)
+
+	fs := configInitCmd.Flags()
+	fs.StringVar(&flagConfigInitMode, "mode", "", "authentication mode: oauth2, cookie, token or basic")
+	fs.StringVarP(&flagConfigInitFile, "file", "f", "", "path of the config file (default $XDG_CONFIG_HOME/camunder/config.yaml or $HOME/.config/camunder/config.yaml)")
+	fs.BoolVar(&flagConfigInitForce, "force", false, "overwrite an existing config file")
+	fs.BoolVar(&flagConfigInitNoInput, "no-input", false, "do not ask for missing values, use the defaults")
+	fs.StringVar(&flagConfigInitCookieBaseURL, "cookie-base-url", "", "base URL of the cookie login (cookie mode)")
+	fs.StringVar(&flagConfigInitCookieUsername, "cookie-username", "", "username of the cookie login (cookie mode)")
+	fs.StringVar(&flagConfigInitBasicUsername, "basic-username", "", "username of the basic authentication (basic mode)")
+}
+
+// configState is what the config commands need beyond the effective config.
+type configState struct {
+	v          *viper.Viper
+	profileErr error // error of the profile selection, tolerated for the config commands
+}
+
+type ctxConfigStateKey struct{}
+
+func (s *configState) toContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxConfigStateKey{}, s)
+}
+
+func configStateFromContext(ctx context.Context) *configState {
+	if s, ok := ctx.Value(ctxConfigStateKey{}).(*configState); ok {
+		return s
+	}
+	return &configState{v: viper.New()}
+}
+
+// configSource names where the effective value of key comes from.
+func configSource(cmd *cobra.Command, v *viper.Viper, profile, key string) string {
+	if flag, ok := flagBindings[key]; ok && cmd.Flags().Changed(flag) {
+		return "flag --" + flag
+	}
+	env := "CAMUNDER_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+	if _, ok := os.LookupEnv(env); ok {
+		return "env " + env
+	}
+	if profile != "" && v.InConfig(config.ProfilesKey+"."+strings.ToLower(profile)+"."+key) {
+		return "profile " + profile
+	}
+	if v.InConfig(key) {
+		return "file"
+	}
+	if key == "auth.oauth2.scopes" && cmd.Flags().Changed("auth-scopes") {
+		return "flag --auth-scopes"
+	}
+	if v.IsSet(key) {
+		return "default"
+	}
+	return "unset"
+}
+
+// configInitOptions collects the values of config init from the flags and, if interactive, the prompts.
+func configInitOptions(cmd *cobra.Command) (config.InitOptions, error) {
+	fs := cmd.Flags()
+	in := bufio.NewReader(cmd.InOrStdin())
+	interactive := !flagConfigInitNoInput && isTerminal(cmd.InOrStdin())
+	ask := func(label, flag string, value *string, def string) {
+		if flag != "" && fs.Changed(flag) {
+			*value, _ = fs.GetString(flag)
+			return
+		}
+		*value = def
+		if !interactive {
+			return
+		}
+		cmd.Print(fmt.Sprintf("%s [%s]: ", label, def))
+		line, _ := in.ReadString('\n')
+		if line = strings.TrimSpace(line); line != "" {
+			*value = line
+		}
+	}
+
+	var mode string
+	ask("Authentication mode (oauth2, cookie, token, basic)", "mode", &mode, string(config.ModeOAuth2))
+	o := config.DefaultInitOptions(config.AuthMode(mode))
+	o.Mode = config.AuthMode(mode)
+	if !o.Mode.IsValid() {
+		return o, fmt.Errorf("mode: %w", config.InvalidModeError(o.Mode))
+	}
+	ask("Camunda API version", "camunda-apis-version", &o.APIsVersion, o.APIsVersion)
+	ask("Camunda API base URL", "camunda-base-url", &o.CamundaBaseURL, o.CamundaBaseURL)
+	ask("Operate API base URL", "operate-base-url", &o.OperateBaseURL, o.OperateBaseURL)
+	ask("Tasklist API base URL", "tasklist-base-url", &o.TasklistBaseURL, o.TasklistBaseURL)
+	ask("Tenant (empty for none)", "tenant", &o.Tenant, o.Tenant)
+	switch o.Mode {
+	case config.ModeOAuth2:
+		ask("Token URL", "auth-token-url", &o.TokenURL, o.TokenURL)
+		ask("Client ID", "auth-client-id", &o.ClientID, o.ClientID)
+	case config.ModeCookie:
+		ask("Cookie login base URL", "cookie-base-url", &o.CookieBaseURL, o.CookieBaseURL)
+		ask("Username", "cookie-username", &o.CookieUsername, o.CookieUsername)
+	case config.ModeBasic:
+		ask("Username", "basic-username", &o.BasicUsername, o.BasicUsername)
+	}
+	return o, nil
+}
+
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// isConfigCommand reports whether cmd belongs to the config command group.
+func isConfigCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == configCmd {
+			return true
+		}
+	}
+	return false
+}
+
+func configFileInUse(cmd *cobra.Command) (string, error) {
+	cfg, err := config.FromContext(cmd.Context())
+	if err != nil {
+		return "", err
+	}
+	if cfg.Config == "" {
+		return "", errors.New("no config file in use, create one or pass it with --config")
+	}
+	return cfg.Config, nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigInit_Cookie(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	out, err := runWithConfig(t, replayConfig, "config", "init", "--mode", "cookie", "--no-input", "--cookie-username", "ops", "-f", path)
+	require.NoError(t, err)
+	require.Contains(t, out, "config written to "+path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `username: "ops"`)
+	require.Contains(t, string(data), "CAMUNDER_AUTH_COOKIE_PASSWORD")
+	require.Contains(t, string(data), `password: ""`, "the password is not written to the file")
+}
+
+func TestConfigInit_Basic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	_, err := runWithConfig(t, replayConfig, "config", "init", "--mode", "basic", "--no-input", "--basic-username", "ci", "-f", path)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `mode: "basic"`)
+	require.Contains(t, string(data), `username: "ci"`)
+	require.Contains(t, string(data), "CAMUNDER_AUTH_BASIC_PASSWORD")
+}
+
+func TestConfigValidate_SecretReference(t *testing.T) {
+	config := `auth:
+  mode: token
+  token:
+    value: env:CAMUNDER_TEST_TOKEN_UNSET
+apis:
+  camunda_api:
+    base_url: http://127.0.0.1:18765/v2
+  operate_api:
+    base_url: http://127.0.0.1:18765
+`
+	out, err := runWithConfig(t, config, "config", "validate")
+	var exit *exitError
+	require.ErrorAs(t, err, &exit)
+	require.Equal(t, exitCodeInvalidConfig, exit.code)
+	require.Contains(t, out, "auth.token.value: environment variable CAMUNDER_TEST_TOKEN_UNSET is not set")
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafvonb/camunder/internal/logging"
+	"github.com/grafvonb/camunder/internal/services/common"
+	"github.com/grafvonb/camunder/internal/services/processinstance"
+	piapi "github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/spf13/cobra"
+)
+
+var supportedResourcesForCreate = common.ResourceTypes{
+	"pi": "process-instance",
+}
+
+var (
+	flagCreateVariables      string
+	flagCreateAwait          bool
+	flagCreateFetchVariables []string
+	flagCreateRequestTimeout time.Duration
+	flagCreateCount          int
+)
+
+// createCmd represents the create command
+var createCmd = &cobra.Command{
+	Use:     "create [resource name]",
+	Short:   "Create resources of a given type, e.g. start process instances. " + supportedResourcesForCreate.PrettyString(),
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"cr", "new", "start"},
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logging.FromContext(cmd.Context())
+		rn := strings.ToLower(args[0])
+		svcs, err := NewFromContext(cmd.Context())
+		if err != nil {
+			log.Error(fmt.Sprintf("%v", err))
+			return
+		}
+
+		switch rn {
+		case "process-instance", "pi":
+			if flagCreateCount < 1 {
+				log.Error(fmt.Sprintf("invalid value for --count: %d (must be greater than 0)", flagCreateCount))
+				return
+			}
+			vars, err := variablesArg(flagCreateVariables)
+			if err != nil {
+				log.Error(fmt.Sprintf("reading variables: %v", err))
+				return
+			}
+			req := piapi.CreateRequest{
+				BpmnProcessId:   flagBpmnProcessID,
+				ProcessVersion:  flagProcessVersion,
+				Variables:       vars,
+				AwaitCompletion: flagCreateAwait,
+				FetchVariables:  flagCreateFetchVariables,
+				RequestTimeout:  flagCreateRequestTimeout,
+			}
+			if flagDryRun {
+				version := "latest version"
+				if req.ProcessVersion > 0 {
+					version = fmt.Sprintf("version %d", req.ProcessVersion)
+				}
+				cmd.Println(fmt.Sprintf("dry-run: would create %d process instance(s) of %s (%s) with %d variable(s), nothing was changed",
+					flagCreateCount, req.BpmnProcessId, version, len(req.Variables)))
+				return
+			}
+			svc, err := processinstance.New(svcs.Config, svcs.HTTP.Client(), log)
+			if err != nil {
+				log.Error(fmt.Sprintf("creating process instance service: %v", err))
+				return
+			}
+			log.Debug(fmt.Sprintf("creating %d process instance(s) of %s", flagCreateCount, req.BpmnProcessId))
+			created := make([]piapi.CreateResponse, flagCreateCount)
+			slots := make([]int, flagCreateCount)
+			for i := range slots {
+				slots[i] = i
+			}
+			results := common.RunBulk(cmd.Context(), slots, flagParallel, func(ctx context.Context, i int) error {
+				var err error
+				created[i], err = svc.CreateProcessInstance(ctx, req)
+				return err
+			})
+			var ok []piapi.CreateResponse
+			var errs []error
+			for _, r := range results {
+				if r.Err != nil {
+					errs = append(errs, fmt.Errorf("instance %d: %w", r.Index+1, r.Err))
+					continue
+				}
+				ok = append(ok, created[r.Index])
+			}
+			if err = createdProcessInstancesView(cmd, ok); err != nil {
+				log.Error(fmt.Sprintf("error rendering created view: %v", err))
+			}
+			if len(errs) > 0 {
+				log.Error(fmt.Sprintf("creating failed for %d of %d process instance(s): %v", len(errs), len(results), errors.Join(errs...)))
+			}
+		default:
+			log.Error(fmt.Sprintf("unknown resource type: %s, supported: %s", rn, supportedResourcesForCreate))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(createCmd)
+
+	fs := createCmd.Flags()
+	fs.StringVarP(&flagBpmnProcessID, "bpmn-process-id", "b", "", "BPMN process ID of the process definition to start")
+	fs.Int32VarP(&flagProcessVersion, "version", "v", 0, "process definition version to start (default latest)")
+	fs.StringVar(&flagCreateVariables, "variables", "", "start variables as JSON object, or @path to a file with the JSON object")
+	fs.BoolVar(&flagCreateAwait, "await-completion", false, "wait until the process instance has completed and return its variables")
+	fs.StringSliceVar(&flagCreateFetchVariables, "fetch-variables", nil, "names of the variables returned with --await-completion (default all)")
+	fs.DurationVar(&flagCreateRequestTimeout, "request-timeout", 0, "how long the server waits for completion with --await-completion (default server setting)")
+	fs.IntVar(&flagCreateCount, "count", 1, "number of process instances to start")
+	fs.IntVar(&flagParallel, "parallel", 0, "number of process instances started in parallel (0 = min(8, --count))")
+
+	fs.BoolVar(&flagKeysOnly, "keys-only", false, "show only keys in output")
+	fs.BoolVar(&flagOneLine, "one-line", false, "output one line per item")
+
+	_ = createCmd.MarkFlagRequired("bpmn-process-id")
+}
+
+// variablesArg decodes a variables flag value given either as JSON object or as @path to a file.
+func variablesArg(v string) (map[string]any, error) {
+	switch {
+	case v == "":
+		return nil, nil
+	case strings.HasPrefix(v, "@"):
+		return readVariables("", strings.TrimPrefix(v, "@"))
+	default:
+		return readVariables(v, "")
+	}
+}
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/grafvonb/camunder/internal/api/convert"
 	"github.com/grafvonb/camunder/internal/logging"
 	"github.com/grafvonb/camunder/internal/services/common"
 	"github.com/grafvonb/camunder/internal/services/processinstance"
@@ -17,14 +19,14 @@ var supportedResourcesForDelete = common.ResourceTypes{
 }
 
 var (
-	flagDeleteKey        int64
+	flagDeleteKeys       []int64
 	flagDeleteWithCancel bool
 )
 
 // deleteCmd represents the delete command
 var deleteCmd = &cobra.Command{
 	Use:     "delete [resource name] [key]",
-	Short:   "Delete a resource of a given type by its key. " + supportedResourcesForDelete.PrettyString(),
+	Short:   "Delete resources of a given type by their keys or by search filter. " + supportedResourcesForDelete.PrettyString(),
 	Args:    cobra.ExactArgs(1),
 	Aliases: []string{"d", "del", "remove", "rm"},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -42,17 +44,40 @@ var deleteCmd = &cobra.Command{
 				log.Error(fmt.Sprintf("creating process instance service: %v", err))
 				return
 			}
-			var pidr piapi.ChangeStatus
-			if flagDeleteWithCancel {
-				pidr, err = svc.DeleteProcessInstanceWithCancel(cmd.Context(), flagDeleteKey)
-			} else {
-				pidr, err = svc.DeleteProcessInstance(cmd.Context(), flagDeleteKey)
-			}
+			pis, err := selectProcessInstances(cmd, svc, flagDeleteKeys)
 			if err != nil {
-				log.Error(fmt.Sprintf("deleting process instance with key %d: %v", flagDeleteKey, err))
+				log.Error(fmt.Sprintf("selecting process instances: %v", err))
+				return
+			}
+			if len(pis) == 0 {
+				log.Info("no process instances selected, nothing to delete")
 				return
 			}
-			log.Debug(pidr.String())
+			if flagDryRun {
+				planned := convert.MapSlice(resolveProcessInstanceStates(cmd, svc, pis), func(pi piapi.ProcessInstance) plannedAction {
+					return planDelete(pi, flagDeleteWithCancel)
+				})
+				dryRunView(cmd, "delete", "process instance(s)", planned)
+				return
+			}
+			log.Debug(fmt.Sprintf("deleting %d process instance(s)", len(pis)))
+			results := common.RunBulk(cmd.Context(), pis, flagParallel, func(ctx context.Context, pi piapi.ProcessInstance) error {
+				var pidr piapi.ChangeStatus
+				var err error
+				if flagDeleteWithCancel {
+					pidr, err = svc.DeleteProcessInstanceWithCancel(ctx, pi.Key)
+				} else {
+					pidr, err = svc.DeleteProcessInstance(ctx, pi.Key)
+				}
+				if err != nil {
+					return err
+				}
+				log.Debug(pidr.String())
+				return nil
+			})
+			if failed := bulkResultView(cmd, "delete", results, processInstanceKey); failed > 0 {
+				log.Error(fmt.Sprintf("deleting failed for %d of %d process instance(s)", failed, len(results)))
+			}
 		default:
 			log.Error(fmt.Sprintf("unknown resource type: %s, supported: %s", rn, supportedResourcesForDelete))
 		}
@@ -64,8 +89,8 @@ func init() {
 
 	AddBackoffFlagsAndBindings(deleteCmd, viper.GetViper())
 
-	deleteCmd.Flags().Int64VarP(&flagDeleteKey, "key", "k", 0, "resource key (e.g. process instance) to delete")
-	_ = deleteCmd.MarkFlagRequired("key")
+	deleteCmd.Flags().Int64SliceVarP(&flagDeleteKeys, "key", "k", nil, "resource key (e.g. process instance) to delete (repeatable or comma-separated)")
+	AddProcessInstanceSelectionFlags(deleteCmd)
 
 	deleteCmd.Flags().BoolVarP(&flagDeleteWithCancel, "cancel", "c", false, "tries to cancel the process instance before deleting it (if not in the state COMPLETED or CANCELED)")
 }
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grafvonb/camunder/internal/logging"
+	"github.com/grafvonb/camunder/internal/services/common"
+	"github.com/grafvonb/camunder/internal/services/incident"
+	"github.com/grafvonb/camunder/internal/services/processinstance"
+	"github.com/grafvonb/camunder/internal/services/variable"
+	"github.com/grafvonb/camunder/pkg/camunda"
+	incapi "github.com/grafvonb/camunder/pkg/camunda/incident"
+	piapi "github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	varapi "github.com/grafvonb/camunder/pkg/camunda/variable"
+	"github.com/spf13/cobra"
+)
+
+var supportedResourcesForDescribe = common.ResourceTypes{
+	"pi": "process-instance",
+}
+
+var (
+	flagDescribeKey int64
+)
+
+// describeCmd represents the describe command
+var describeCmd = &cobra.Command{
+	Use:   "describe [resource name] [key]",
+	Short: "Describe a resource of a given type in detail, e.g. a process instance with its element timeline. " + supportedResourcesForDescribe.PrettyString(),
+	Long: "Describe a process instance: the instance itself, a timeline of its element instances ordered by start " +
+		"(with the incident message where an element failed), the sequence flows taken, element statistics, incidents and variables.",
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"desc"},
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logging.FromContext(cmd.Context())
+		rn := strings.ToLower(args[0])
+		svcs, err := NewFromContext(cmd.Context())
+		if err != nil {
+			log.Error(fmt.Sprintf("%v", err))
+			return
+		}
+
+		switch rn {
+		case "process-instance", "pi":
+			piSvc, err := processinstance.New(svcs.Config, svcs.HTTP.Client(), log)
+			if err != nil {
+				log.Error(fmt.Sprintf("creating process instance service: %v", err))
+				return
+			}
+			incSvc, err := incident.New(svcs.Config, svcs.HTTP.Client(), log)
+			if err != nil {
+				log.Error(fmt.Sprintf("creating incident service: %v", err))
+				return
+			}
+			varSvc, err := variable.New(svcs.Config, svcs.HTTP.Client(), log)
+			if err != nil {
+				log.Error(fmt.Sprintf("creating variable service: %v", err))
+				return
+			}
+			d, err := describeProcessInstance(cmd, piSvc, incSvc, varSvc, flagDescribeKey)
+			if err != nil {
+				log.Error(fmt.Sprintf("describing process instance %d: %v", flagDescribeKey, err))
+				return
+			}
+			if flagOutput != "" {
+				if err = renderOutput(cmd, d, d.Timeline); err != nil {
+					log.Error(fmt.Sprintf("rendering description: %v", err))
+				}
+				return
+			}
+			processInstanceDescriptionView(cmd, d)
+		default:
+			log.Error(fmt.Sprintf("unknown resource type: %s, supported: %s", rn, supportedResourcesForDescribe))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(describeCmd)
+
+	fs := describeCmd.Flags()
+	fs.Int64VarP(&flagDescribeKey, "key", "k", 0, "key of the resource to describe")
+	_ = describeCmd.MarkFlagRequired("key")
+}
+
+// processInstanceDescription is the whole story of one process instance.
+type processInstanceDescription struct {
+	ProcessInstance piapi.ProcessInstance     `json:"processInstance"`
+	Timeline        []timelineEntry           `json:"timeline"`
+	SequenceFlows   []string                  `json:"sequenceFlows"`
+	Statistics      []piapi.ElementStatistics `json:"statistics"`
+	Incidents       []incapi.Incident         `json:"incidents"`
+	Variables       []varapi.Variable         `json:"variables"`
+}
+
+// timelineEntry is one element instance of the process instance.
+type timelineEntry struct {
+	Key       int64  `json:"key"`
+	ElementId string `json:"elementId"`
+	Name      string `json:"name,omitempty"`
+	Type      string `json:"type"`
+	State     string `json:"state"`
+	StartDate string `json:"startDate,omitempty"`
+	EndDate   string `json:"endDate,omitempty"`
+	Incident  string `json:"incident,omitempty"`
+}
+
+func describeProcessInstance(cmd *cobra.Command, piSvc piapi.API, incSvc incapi.API, varSvc varapi.API, key int64) (processInstanceDescription, error) {
+	ctx := cmd.Context()
+	var d processInstanceDescription
+	var err error
+	if d.ProcessInstance, err = piSvc.GetProcessInstanceByKey(ctx, key); err != nil {
+		return d, err
+	}
+	elements, err := piSvc.GetElementInstancesOfProcessInstance(ctx, key)
+	if err != nil {
+		return d, fmt.Errorf("fetching element instances: %w", err)
+	}
+	if d.SequenceFlows, err = piSvc.GetSequenceFlowsOfProcessInstance(ctx, key); err != nil {
+		return d, fmt.Errorf("fetching sequence flows: %w", err)
+	}
+	if d.Statistics, err = piSvc.GetElementStatisticsOfProcessInstance(ctx, key); err != nil {
+		return d, fmt.Errorf("fetching element statistics: %w", err)
+	}
+	incs, _, err := camunda.SearchAll(ctx, incSvc.SearchIncidentsPage, incapi.SearchFilterOpts{ProcessInstanceKey: key}, defaultSearchPageSize, 0)
+	if err != nil {
+		return d, fmt.Errorf("fetching incidents: %w", err)
+	}
+	d.Incidents = incs
+	vars, _, err := camunda.SearchAll(ctx, varSvc.SearchVariablesPage, varapi.SearchFilterOpts{ProcessInstanceKey: key}, defaultSearchPageSize, 0)
+	if err != nil {
+		return d, fmt.Errorf("fetching variables: %w", err)
+	}
+	if err = fetchFullVariableValues(cmd, varSvc, vars); err != nil {
+		return d, fmt.Errorf("fetching variables: %w", err)
+	}
+	d.Variables = vars
+	d.Timeline = buildTimeline(elements, d.Incidents)
+	return d, nil
+}
+
+// buildTimeline orders the element instances by start and attaches the message of their incident.
+func buildTimeline(elements []piapi.ElementInstance, incs []incapi.Incident) []timelineEntry {
+	messages := make(map[int64]string, len(incs))
+	for _, inc := range incs {
+		messages[inc.Key] = inc.ErrorMessage
+	}
+	out := make([]timelineEntry, 0, len(elements))
+	for _, e := range elements {
+		te := timelineEntry{
+			Key:       e.Key,
+			ElementId: e.ElementId,
+			Name:      e.Name,
+			Type:      e.Type,
+			State:     e.State,
+			StartDate: e.StartDate,
+			EndDate:   e.EndDate,
+		}
+		if e.Incident {
+			te.Incident = messages[e.IncidentKey]
+			if te.Incident == "" {
+				te.Incident = "incident"
+			}
+		}
+		out = append(out, te)
+	}
+	slices.SortStableFunc(out, func(a, b timelineEntry) int {
+		if c := strings.Compare(a.StartDate, b.StartDate); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return out
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	incapi "github.com/grafvonb/camunder/pkg/camunda/incident"
+	piapi "github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	elements := []piapi.ElementInstance{
+		{Key: 4, ElementId: "pay", State: "ACTIVE", StartDate: "2025-09-01T10:02:00Z", Incident: true, IncidentKey: 9},
+		{Key: 2, ElementId: "check", State: "COMPLETED", StartDate: "2025-09-01T10:01:00Z"},
+		{Key: 1, ElementId: "start", State: "COMPLETED", StartDate: "2025-09-01T10:00:00Z"},
+		{Key: 3, ElementId: "notify", State: "ACTIVE", StartDate: "2025-09-01T10:01:00Z", Incident: true, IncidentKey: 8},
+	}
+	incs := []incapi.Incident{{Key: 9, ErrorMessage: "card declined"}}
+
+	timeline := buildTimeline(elements, incs)
+	keys := make([]int64, 0, len(timeline))
+	for _, te := range timeline {
+		keys = append(keys, te.Key)
+	}
+	require.Equal(t, []int64{1, 2, 3, 4}, keys, "ordered by start, then by key")
+	require.Equal(t, "", timeline[0].Incident)
+	require.Equal(t, "incident", timeline[2].Incident, "an incident that was not fetched is still marked")
+	require.Equal(t, "card declined", timeline[3].Incident)
+}
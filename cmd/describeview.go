@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// processInstanceDescriptionView prints the description as text, one section after the other.
+func processInstanceDescriptionView(cmd *cobra.Command, d processInstanceDescription) {
+	pi := d.ProcessInstance
+	parent := "<root>"
+	if pi.ParentKey > 0 {
+		parent = fmt.Sprint(pi.ParentKey)
+	}
+	cmd.Println(fmt.Sprintf("Process instance %d", pi.Key))
+	cmd.Println(fmt.Sprintf("  process:   %s v%d (definition %d)", pi.BpmnProcessId, pi.ProcessVersion, pi.ProcessDefinitionKey))
+	cmd.Println(fmt.Sprintf("  state:     %s", pi.State))
+	cmd.Println(fmt.Sprintf("  started:   %s", pi.StartDate))
+	cmd.Println(fmt.Sprintf("  ended:     %s", orDash(pi.EndDate)))
+	cmd.Println(fmt.Sprintf("  parent:    %s", parent))
+	cmd.Println(fmt.Sprintf("  tenant:    %s", orDash(pi.TenantId)))
+	cmd.Println(fmt.Sprintf("  incident:  %t", pi.Incident))
+
+	cmd.Println()
+	cmd.Println(fmt.Sprintf("Timeline (%d element instances)", len(d.Timeline)))
+	for _, e := range d.Timeline {
+		line := fmt.Sprintf("  %-29s %-29s %-10s %-22s %s", e.StartDate, orDash(e.EndDate), e.State, e.Type, e.ElementId)
+		if e.Incident != "" {
+			line += "  ! " + e.Incident
+		}
+		cmd.Println(line)
+	}
+
+	if len(d.SequenceFlows) > 0 {
+		cmd.Println()
+		cmd.Println(fmt.Sprintf("Sequence flows taken: %s", strings.Join(d.SequenceFlows, ", ")))
+	}
+
+	if len(d.Statistics) > 0 {
+		cmd.Println()
+		cmd.Println("Element statistics (active/completed/canceled/incidents)")
+		for _, st := range d.Statistics {
+			cmd.Println(fmt.Sprintf("  %-30s %d/%d/%d/%d", st.ElementId, st.Active, st.Completed, st.Canceled, st.Incidents))
+		}
+	}
+
+	cmd.Println()
+	cmd.Println(fmt.Sprintf("Incidents (%d)", len(d.Incidents)))
+	for _, inc := range d.Incidents {
+		cmd.Println(fmt.Sprintf("  %-16d %-10s %-20s %s", inc.Key, inc.State, inc.ErrorType, inc.ErrorMessage))
+	}
+
+	cmd.Println()
+	cmd.Println(fmt.Sprintf("Variables (%d)", len(d.Variables)))
+	for _, v := range d.Variables {
+		val, err := json.Marshal(v.Value)
+		if err != nil {
+			val = []byte(fmt.Sprint(v.Value))
+		}
+		cmd.Println(fmt.Sprintf("  %s = %s", v.Name, val))
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	incapi "github.com/grafvonb/camunder/pkg/camunda/incident"
+	piapi "github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/spf13/cobra"
+)
+
+// plannedAction is what a mutating command would do to one resource in dry-run mode.
+type plannedAction struct {
+	Key    int64
+	State  string
+	Action string
+}
+
+// Pseudo states shown in dry-run for resources whose current state could not be fetched;
+// no Camunda API reports them.
+const (
+	dryRunStateNotFound = "NOT_FOUND"
+	dryRunStateUnknown  = "UNKNOWN"
+)
+
+// resolveProcessInstanceStates fetches the current state of instances selected by key only.
+// Instances that cannot be fetched keep their key and get a dry-run pseudo state.
+func resolveProcessInstanceStates(cmd *cobra.Command, svc piapi.API, pis []piapi.ProcessInstance) []piapi.ProcessInstance {
+	out := make([]piapi.ProcessInstance, 0, len(pis))
+	for _, pi := range pis {
+		if pi.State != "" {
+			out = append(out, pi)
+			continue
+		}
+		got, err := svc.GetProcessInstanceByKey(cmd.Context(), pi.Key)
+		if err != nil {
+			state := dryRunStateUnknown
+			if errors.Is(err, piapi.ErrNotFound) {
+				state = dryRunStateNotFound
+			}
+			out = append(out, piapi.ProcessInstance{Key: pi.Key, State: piapi.State(state)})
+			continue
+		}
+		out = append(out, got)
+	}
+	return out
+}
+
+func planCancel(pi piapi.ProcessInstance) plannedAction {
+	a := plannedAction{Key: pi.Key, State: strings.ToUpper(pi.State.String())}
+	switch {
+	case pi.State.EqualsIgnoreCase(piapi.StateActive):
+		a.Action = "cancel"
+	case pi.State == dryRunStateNotFound:
+		a.Action = "cancel (expected to fail: not found)"
+	case pi.State == dryRunStateUnknown:
+		a.Action = "cancel (current state could not be fetched)"
+	default:
+		a.Action = "cancel (expected to fail: not active)"
+	}
+	return a
+}
+
+func planDelete(pi piapi.ProcessInstance, withCancel bool) plannedAction {
+	a := plannedAction{Key: pi.Key, State: strings.ToUpper(pi.State.String())}
+	switch {
+	case pi.State.EqualsIgnoreCase(piapi.StateCompleted), pi.State.EqualsIgnoreCase(piapi.StateCanceled):
+		a.Action = "delete"
+	case pi.State.EqualsIgnoreCase(piapi.StateActive) && withCancel:
+		a.Action = "cancel, wait for state CANCELED, then delete"
+	case pi.State.EqualsIgnoreCase(piapi.StateActive):
+		a.Action = "delete (expected to fail: not COMPLETED or CANCELED, use --cancel)"
+	case pi.State == dryRunStateNotFound:
+		a.Action = "delete (expected to fail: not found)"
+	default:
+		a.Action = "delete (current state could not be fetched)"
+	}
+	return a
+}
+
+// resolveIncidentStates fetches the current state of incidents selected by key only.
+func resolveIncidentStates(cmd *cobra.Command, svc incapi.API, incs []incapi.Incident) []incapi.Incident {
+	out := make([]incapi.Incident, 0, len(incs))
+	for _, inc := range incs {
+		if inc.State != "" {
+			out = append(out, inc)
+			continue
+		}
+		got, err := svc.GetIncidentByKey(cmd.Context(), inc.Key)
+		if err != nil {
+			state := dryRunStateUnknown
+			if errors.Is(err, incapi.ErrNotFound) {
+				state = dryRunStateNotFound
+			}
+			out = append(out, incapi.Incident{Key: inc.Key, State: incapi.State(state)})
+			continue
+		}
+		out = append(out, got)
+	}
+	return out
+}
+
+func planResolve(inc incapi.Incident) plannedAction {
+	a := plannedAction{Key: inc.Key, State: strings.ToUpper(inc.State.String())}
+	switch {
+	case inc.State.EqualsIgnoreCase(incapi.StateActive):
+		a.Action = "resolve"
+	case inc.State == dryRunStateNotFound:
+		a.Action = "resolve (expected to fail: not found)"
+	case inc.State == dryRunStateUnknown:
+		a.Action = "resolve (current state could not be fetched)"
+	default:
+		a.Action = "resolve (expected to fail: not active)"
+	}
+	return a
+}
+
+func dryRunView(cmd *cobra.Command, action, what string, planned []plannedAction) {
+	cmd.Println(fmt.Sprintf("dry-run: would %s %d %s, nothing was changed", action, len(planned), what))
+	for _, p := range planned {
+		cmd.Println(fmt.Sprintf("%-16d %-10s %s", p.Key, p.State, p.Action))
+	}
+}
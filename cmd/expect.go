@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 
 	"github.com/grafvonb/camunder/internal/logging"
 	"github.com/grafvonb/camunder/internal/services/common"
 	"github.com/grafvonb/camunder/internal/services/processinstance"
+	"github.com/grafvonb/camunder/internal/services/variable"
 	piapi "github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	varapi "github.com/grafvonb/camunder/pkg/camunda/variable"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -16,48 +23,116 @@ var supportedResourcesForExpect = common.ResourceTypes{
 	"pi": "process-instance",
 }
 
+// exit codes of expect, other errors exit with 1
+const (
+	exitCodeExpectFailed  = 3 // a condition can no longer be met
+	exitCodeExpectTimeout = 4 // the conditions were not met in time
+)
+
 var (
-	flagExpectKey int64
+	flagExpectKeys        []int64
+	flagExpectStdin       bool
+	flagExpectState       string
+	flagExpectHasIncident bool
+	flagExpectNoIncident  bool
+	flagExpectElementId   string
+	flagExpectVars        []string
+	flagExpectAll         bool
+	flagExpectAny         bool
 )
 
-// expectCmd represents the cancel command
+// expectConditionFlags are the flags of which expect needs at least one.
+var expectConditionFlags = []string{"state", "has-incident", "no-incident", "element-id", "var"}
+
+// expectCmd represents the expect command
 var expectCmd = &cobra.Command{
-	Use:     "expect [resource name] [key]",
-	Short:   "Expect a resource of a given type to change (e.g. its state) by its key. " + supportedResourcesForExpect.PrettyString(),
+	Use:   "expect [resource name]",
+	Short: "Expect resources of a given type to meet conditions (e.g. a state) and wait until they do. " + supportedResourcesForExpect.PrettyString(),
+	Long: "Expect resources of a given type to meet conditions and wait until they do, polling with the backoff settings. " +
+		"Process instances are selected by --key, by keys read from standard input (--stdin) or by search filter. " +
+		"All given conditions must hold at once, by all instances or, with --any, by one of them. " +
+		fmt.Sprintf("Exits with %d if a condition can no longer be met, e.g. an instance ended in another state or none was selected, ", exitCodeExpectFailed) +
+		fmt.Sprintf("and with %d if the conditions were not met before --backoff-timeout.", exitCodeExpectTimeout),
+	Example: `  camunder expect pi --key 2251799813685251 --state completed
+  camunder expect pi --bpmn-process-id order-process --state completed --var 'approved=true' --var 'amount>=100'
+  camunder get pi --state active -o 'jsonpath={.items[*].key}' | camunder expect pi --stdin --any --element-id ship-order`,
 	Args:    cobra.ExactArgs(1),
 	Aliases: []string{"e", "exp", "await"},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		log := logging.FromContext(cmd.Context())
-		if err := requireAnyFlag(cmd, "state"); err != nil {
-			log.Error(err.Error())
-			return
+		if err := requireAnyFlag(cmd, expectConditionFlags...); err != nil {
+			return err
 		}
 		rn := strings.ToLower(args[0])
 		svcs, err := NewFromContext(cmd.Context())
 		if err != nil {
-			log.Error(fmt.Sprintf("Error initializing service from context: %v", err))
-			return
+			return fmt.Errorf("initializing service from context: %w", err)
 		}
 
 		switch rn {
 		case "process-instance", "pi":
+			cond, err := expectCondition()
+			if err != nil {
+				return err
+			}
 			svc, err := processinstance.New(svcs.Config, svcs.HTTP.Client(), log)
 			if err != nil {
-				log.Error(fmt.Sprintf("error creating process instance service: %v", err))
-				return
+				return fmt.Errorf("creating process instance service: %w", err)
+			}
+			keys, err := expectedProcessInstanceKeys(cmd, svc)
+			if err != nil {
+				return err
+			}
+			if len(keys) == 0 {
+				// an empty selection meets no condition, e.g. a pipeline whose first command found nothing
+				return &exitError{code: exitCodeExpectFailed, err: errors.New("no process instances selected")}
 			}
 
-			state, err := piapi.ParseState(flagState)
-			if err == nil && state != piapi.StateAll {
-				log.Info(fmt.Sprintf("waiting for process instance %d to reach state %q", flagExpectKey, state))
-				err = svc.WaitForProcessInstanceState(cmd.Context(), flagExpectKey, state)
+			var getVar piapi.VariableGetter
+			if len(cond.Variables) > 0 {
+				vsvc, err := variable.New(svcs.Config, svcs.HTTP.Client(), log)
 				if err != nil {
-					log.Error(fmt.Sprintf("error waiting for a process instance to reach a %q state: %v", state, err))
-					return
+					return fmt.Errorf("creating variable service: %w", err)
 				}
+				getVar = processVariableGetter(vsvc)
+			}
+
+			backoff := svcs.Config.App.Backoff
+			ctx := cmd.Context()
+			if backoff.Timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, backoff.Timeout)
+				defer cancel()
+			}
+			opts := []piapi.ExpectOption{
+				piapi.WithExpectParallel(flagParallel),
+				piapi.WithPollDelay(backoff.InitialDelay, backoff.NextDelay),
+				piapi.WithMaxPolls(backoff.MaxRetries),
+				piapi.WithOnPoll(func(results []piapi.ExpectResult) {
+					for _, r := range results {
+						if r.Err != nil {
+							log.Warn(fmt.Sprintf("observing process instance %d failed: %v (will retry)", r.Key, r.Err))
+						}
+					}
+				}),
 			}
+			if flagExpectAny {
+				opts = append(opts, piapi.WithExpectAny())
+			}
+			log.Info(fmt.Sprintf("waiting for %d process instance(s) to meet: %s", len(keys), cond))
+			results, err := piapi.Expect(ctx, func(ctx context.Context, key int64) (piapi.Observation, error) {
+				return piapi.Observe(ctx, svc, getVar, key, cond)
+			}, keys, cond, opts...)
+			expectResultView(cmd, results)
+			switch {
+			case errors.Is(err, piapi.ErrConditionFailed):
+				return &exitError{code: exitCodeExpectFailed, err: err}
+			case errors.Is(err, piapi.ErrExpectTimeout):
+				return &exitError{code: exitCodeExpectTimeout, err: err}
+			}
+			return err
 		default:
-			log.Error(fmt.Sprintf("unknown resource type %q, supported: %s", rn, supportedResourcesForGet))
+			return fmt.Errorf("unknown resource type %q, supported: %s", rn, supportedResourcesForExpect)
 		}
 	},
 }
@@ -67,8 +142,144 @@ func init() {
 
 	AddBackoffFlagsAndBindings(expectCmd, viper.GetViper())
 
-	expectCmd.Flags().Int64VarP(&flagExpectKey, "key", "k", 0, "resource key (e.g. process instance)")
-	_ = expectCmd.MarkFlagRequired("key")
+	fs := expectCmd.Flags()
+	fs.Int64SliceVarP(&flagExpectKeys, "key", "k", nil, "resource key (e.g. process instance) to expect (repeatable or comma-separated)")
+	fs.BoolVar(&flagExpectStdin, "stdin", false, "read the keys from standard input, separated by whitespace or commas")
+	fs.StringVarP(&flagBpmnProcessID, "bpmn-process-id", "b", "", "select process instances by BPMN process ID")
+	fs.Int32VarP(&flagProcessVersion, "process-version", "v", 0, "select process instances by process definition version")
+	fs.Int64Var(&flagParentKey, "parent-key", 0, "select process instances by parent process instance key")
+	fs.IntVar(&flagParallel, "parallel", 0, fmt.Sprintf("number of process instances polled in parallel (0 = %d)", piapi.DefaultWalkParallel))
+
+	fs.StringVarP(&flagExpectState, "state", "s", "", "state of a process instance: active, completed, canceled or absent (not found)")
+	fs.BoolVar(&flagExpectHasIncident, "has-incident", false, "the process instance has an incident")
+	fs.BoolVar(&flagExpectNoIncident, "no-incident", false, "the process instance has no incident")
+	fs.StringVar(&flagExpectElementId, "element-id", "", "the process instance has reached the element (flow node) with this ID")
+	fs.StringArrayVar(&flagExpectVars, "var", nil, "a variable of the process instance meets the predicate, e.g. approved=true, amount>=100, status!=\"failed\" or just a name for it to exist (repeatable)")
+	fs.BoolVar(&flagExpectAll, "all", false, "all process instances must meet the conditions (default)")
+	fs.BoolVar(&flagExpectAny, "any", false, "one process instance meeting the conditions suffices")
+
+	expectCmd.MarkFlagsMutuallyExclusive("has-incident", "no-incident")
+	expectCmd.MarkFlagsMutuallyExclusive("all", "any")
+}
+
+// expectCondition builds the condition from the condition flags.
+func expectCondition() (piapi.Condition, error) {
+	var cond piapi.Condition
+	if flagExpectState != "" {
+		state, err := piapi.ParseExpectedState(flagExpectState)
+		if err != nil {
+			return cond, err
+		}
+		cond.State = state
+	}
+	if flagExpectHasIncident || flagExpectNoIncident {
+		cond.Incident = &flagExpectHasIncident
+	}
+	cond.ElementId = flagExpectElementId
+	for _, s := range flagExpectVars {
+		p, err := piapi.ParseVariablePredicate(s)
+		if err != nil {
+			return cond, err
+		}
+		cond.Variables = append(cond.Variables, p)
+	}
+	return cond, cond.Validate()
+}
+
+// expectedProcessInstanceKeys returns the keys given by --key and --stdin or else those of the
+// process instances matching the search filter flags.
+func expectedProcessInstanceKeys(cmd *cobra.Command, svc piapi.API) ([]int64, error) {
+	keys := flagExpectKeys
+	if flagExpectStdin {
+		read, err := readKeys(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("reading keys from standard input: %w", err)
+		}
+		keys = append(keys, read...)
+	}
+	if len(keys) > 0 || flagExpectStdin {
+		return keys, nil
+	}
+	if err := requireAnyFlag(cmd, "key", "stdin", "bpmn-process-id", "process-version", "parent-key"); err != nil {
+		return nil, err
+	}
+	pis, err := selectProcessInstances(cmd, svc, nil)
+	if err != nil {
+		return nil, fmt.Errorf("selecting process instances: %w", err)
+	}
+	keys = make([]int64, 0, len(pis))
+	for _, pi := range pis {
+		keys = append(keys, pi.Key)
+	}
+	return keys, nil
+}
+
+// readKeys reads keys separated by whitespace or commas, e.g. the output of get -o jsonpath or --keys-only.
+func readKeys(r io.Reader) ([]int64, error) {
+	sc := bufio.NewScanner(r)
+	sc.Split(bufio.ScanWords)
+	var keys []int64
+	for sc.Scan() {
+		for _, f := range strings.Split(sc.Text(), ",") {
+			if f == "" {
+				continue
+			}
+			k, err := strconv.ParseInt(f, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key %q", f)
+			}
+			keys = append(keys, k)
+		}
+	}
+	return keys, sc.Err()
+}
+
+// processVariableGetter looks a variable up by name; the one of the process scope wins over local
+// ones of the same name, and truncated values are fetched in full.
+func processVariableGetter(svc varapi.API) piapi.VariableGetter {
+	return func(ctx context.Context, key int64, name string) (any, bool, error) {
+		vars, err := svc.SearchVariables(ctx, varapi.SearchFilterOpts{ProcessInstanceKey: key, Name: name}, defaultSearchPageSize)
+		if err != nil {
+			return nil, false, err
+		}
+		var found *varapi.Variable
+		for i, v := range vars.Items {
+			if v.Name == name && (found == nil || v.ScopeKey == key) {
+				found = &vars.Items[i]
+			}
+		}
+		if found == nil {
+			return nil, false, nil
+		}
+		if found.Truncated {
+			full, err := svc.GetVariableByKey(ctx, found.Key)
+			if err != nil {
+				return nil, false, err
+			}
+			return full.Value, true, nil
+		}
+		return found.Value, true, nil
+	}
+}
 
-	expectCmd.Flags().StringVarP(&flagState, "state", "s", "", "state of a process instance: active, completed, canceled or absent")
+// expectResultView prints the last verdict per process instance and a summary.
+func expectResultView(cmd *cobra.Command, results []piapi.ExpectResult) {
+	var met, failed int
+	for _, r := range results {
+		switch {
+		case r.Verdict == piapi.Met:
+			met++
+			cmd.Println(fmt.Sprintf("%-16d met", r.Key))
+		case r.Verdict == piapi.Failed:
+			failed++
+			cmd.Println(fmt.Sprintf("%-16d failed: %s", r.Key, r.Reason))
+		case r.Err != nil:
+			cmd.Println(fmt.Sprintf("%-16d pending: %v", r.Key, r.Err))
+		default:
+			cmd.Println(fmt.Sprintf("%-16d pending: %s", r.Key, r.Reason))
+		}
+	}
+	if len(results) > 0 {
+		cmd.Println(fmt.Sprintf("expect: %d total, %d met, %d failed, %d pending", len(results), met, failed, len(results)-met-failed))
+	}
 }
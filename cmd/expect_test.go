@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadKeys(t *testing.T) {
+	keys, err := readKeys(strings.NewReader("2251799813685251 2251799813685260\n2251799813685270,2251799813685280,\n"))
+	require.NoError(t, err)
+	require.Equal(t, []int64{2251799813685251, 2251799813685260, 2251799813685270, 2251799813685280}, keys)
+
+	_, err = readKeys(strings.NewReader("2251799813685251 order-process"))
+	require.ErrorContains(t, err, `invalid key "order-process"`)
+}
+
+func TestExpect_NoInstancesSelected(t *testing.T) {
+	rootCmd.SetIn(strings.NewReader(""))
+	t.Cleanup(func() { rootCmd.SetIn(nil) })
+
+	_, err := runWithConfig(t, replayConfig, "-a", "8.8", "expect", "pi", "--stdin", "--state", "completed")
+	var exit *exitError
+	require.ErrorAs(t, err, &exit)
+	require.Equal(t, exitCodeExpectFailed, exit.code)
+	require.ErrorContains(t, err, "no process instances selected")
+}
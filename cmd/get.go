@@ -7,20 +7,30 @@ import (
 	"github.com/grafvonb/camunder/internal/logging"
 	"github.com/grafvonb/camunder/internal/services/cluster"
 	"github.com/grafvonb/camunder/internal/services/common"
+	"github.com/grafvonb/camunder/internal/services/incident"
 	"github.com/grafvonb/camunder/internal/services/processdefinition"
 	"github.com/grafvonb/camunder/internal/services/processinstance"
+	"github.com/grafvonb/camunder/internal/services/variable"
+	"github.com/grafvonb/camunder/pkg/camunda"
+	incapi "github.com/grafvonb/camunder/pkg/camunda/incident"
 	pdapi "github.com/grafvonb/camunder/pkg/camunda/processdefinition"
 	piapi "github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	varapi "github.com/grafvonb/camunder/pkg/camunda/variable"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-const maxSearchSize int32 = 1000
+const (
+	defaultSearchPageSize int32 = 1000
+	defaultSearchLimit          = 1000
+)
 
 var supportedResourcesForGet = common.ResourceTypes{
-	"ct": "cluster-topology",
-	"pd": "process-definition",
-	"pi": "process-instance",
+	"ct":  "cluster-topology",
+	"inc": "incident",
+	"var": "variable",
+	"pd":  "process-definition",
+	"pi":  "process-instance",
 }
 
 // filter options
@@ -42,6 +52,18 @@ var (
 	flagNoIncidentsOnly   bool
 )
 
+// paging options
+var (
+	flagLimit    int
+	flagAll      bool
+	flagPageSize int32
+)
+
+// variable filter options
+var (
+	flagVariableName string
+)
+
 // view options
 var (
 	flagKeysOnly bool
@@ -56,6 +78,10 @@ var getCmd = &cobra.Command{
 	Aliases: []string{"g", "list", "ls", "g"},
 	Run: func(cmd *cobra.Command, args []string) {
 		log := logging.FromContext(cmd.Context())
+		if flagPageSize <= 0 {
+			log.Error(fmt.Sprintf("invalid value for --page-size: %d (must be greater than 0)", flagPageSize))
+			return
+		}
 		rn := strings.ToLower(args[0])
 		svcs, err := NewFromContext(cmd.Context())
 		if err != nil {
@@ -76,7 +102,9 @@ var getCmd = &cobra.Command{
 				log.Error(fmt.Sprintf("error fetching topology: %v", err))
 				return
 			}
-			cmd.Println(ToJSONString(topology))
+			if err = clusterTopologyView(cmd, topology); err != nil {
+				log.Error(fmt.Sprintf("error rendering topology: %v", err))
+			}
 
 		case "process-definition", "pd":
 			log.Debug("fetching process definitions")
@@ -100,11 +128,13 @@ var getCmd = &cobra.Command{
 				}
 			} else {
 				log.Debug(fmt.Sprintf("searching by filter: %v", searchFilterOpts))
-				pdsr, err := svc.SearchProcessDefinitions(cmd.Context(), searchFilterOpts, maxSearchSize)
+				items, total, err := camunda.SearchAll(cmd.Context(), svc.SearchProcessDefinitionsPage, searchFilterOpts, flagPageSize, searchLimit())
 				if err != nil {
 					log.Error(fmt.Sprintf("error fetching process definitions: %v", err))
 					return
 				}
+				pdsr := pdapi.ProcessDefinitions{Total: int32(total), Items: items}
+				logTruncated(cmd, "process definitions", len(pdsr.Items), pdsr.Total)
 				if flagKeysOnly {
 					err = listKeyOnlyProcessDefinitionsView(cmd, pdsr)
 					if err != nil {
@@ -142,34 +172,11 @@ var getCmd = &cobra.Command{
 				log.Debug(fmt.Sprintf("searched by key, found process instance with key: %d", pi.Key))
 			} else {
 				log.Debug(fmt.Sprintf("searching by filter: %v", searchFilterOpts))
-				pisr, err := svc.SearchForProcessInstances(cmd.Context(), searchFilterOpts, maxSearchSize)
+				pisr, err := searchProcessInstances(cmd, svc, searchFilterOpts, flagPageSize, searchLimit())
 				if err != nil {
 					log.Error(fmt.Sprintf("error fetching process instances: %v", err))
 					return
 				}
-				if flagChildrenOnly && flagParentsOnly {
-					log.Error("using both --children-only and --parents-only filters returns always no results")
-					return
-				}
-				if flagChildrenOnly {
-					pisr = pisr.FilterChildrenOnly()
-				}
-				if flagParentsOnly {
-					pisr = pisr.FilterParentsOnly()
-				}
-				if flagOrphanParentsOnly {
-					pisr.Items, err = svc.FilterProcessInstanceWithOrphanParent(cmd.Context(), pisr.Items)
-					if err != nil {
-						log.Error(fmt.Sprintf("error filtering orphan parents: %v", err))
-						return
-					}
-				}
-				if flagIncidentsOnly {
-					pisr = pisr.FilterByHavingIncidents(true)
-				}
-				if flagNoIncidentsOnly {
-					pisr = pisr.FilterByHavingIncidents(false)
-				}
 				if flagKeysOnly {
 					err = listKeyOnlyProcessInstancesView(cmd, pisr)
 					if err != nil {
@@ -184,6 +191,96 @@ var getCmd = &cobra.Command{
 				log.Debug(fmt.Sprintf("fetched process instances: %d", pisr.Total))
 			}
 
+		case "incident", "inc":
+			log.Debug("fetching incidents")
+			searchFilterOpts := populateIncidentSearchFilterOpts()
+			svc, err := incident.New(svcs.Config, svcs.HTTP.Client(), log)
+			if err != nil {
+				log.Error(fmt.Sprintf("error creating incident service: %v", err))
+				return
+			}
+			if searchFilterOpts.Key > 0 {
+				log.Debug(fmt.Sprintf("searching by key: %d", searchFilterOpts.Key))
+				inc, err := svc.GetIncidentByKey(cmd.Context(), searchFilterOpts.Key)
+				if err != nil {
+					log.Error(fmt.Sprintf("error fetching incident by key %d: %v", searchFilterOpts.Key, err))
+					return
+				}
+				err = incidentView(cmd, inc)
+				if err != nil {
+					log.Error(fmt.Sprintf("error rendering key-only view: %v", err))
+				}
+				return
+			}
+			log.Debug(fmt.Sprintf("searching by filter: %v", searchFilterOpts))
+			items, total, err := camunda.SearchAll(cmd.Context(), svc.SearchIncidentsPage, searchFilterOpts, flagPageSize, searchLimit())
+			if err != nil {
+				log.Error(fmt.Sprintf("error fetching incidents: %v", err))
+				return
+			}
+			incs := incapi.Incidents{Total: int32(total), Items: items}
+			logTruncated(cmd, "incidents", len(incs.Items), incs.Total)
+			if flagKeysOnly {
+				err = listKeyOnlyIncidentsView(cmd, incs)
+				if err != nil {
+					log.Error(fmt.Sprintf("error rendering keys-only view: %v", err))
+				}
+				return
+			}
+			err = listIncidentsView(cmd, incs)
+			if err != nil {
+				log.Error(fmt.Sprintf("error rendering items view: %v", err))
+			}
+
+		case "variable", "var":
+			log.Debug("fetching variables")
+			svc, err := variable.New(svcs.Config, svcs.HTTP.Client(), log)
+			if err != nil {
+				log.Error(fmt.Sprintf("error creating variable service: %v", err))
+				return
+			}
+			if flagKey > 0 {
+				log.Debug(fmt.Sprintf("searching by key: %d", flagKey))
+				v, err := svc.GetVariableByKey(cmd.Context(), flagKey)
+				if err != nil {
+					log.Error(fmt.Sprintf("error fetching variable by key %d: %v", flagKey, err))
+					return
+				}
+				err = variableView(cmd, v)
+				if err != nil {
+					log.Error(fmt.Sprintf("error rendering key-only view: %v", err))
+				}
+				return
+			}
+			if err = requireAnyFlag(cmd, "key", "process-instance-key"); err != nil {
+				log.Error(err.Error())
+				return
+			}
+			searchFilterOpts := varapi.SearchFilterOpts{ProcessInstanceKey: flagProcessInstanceKey, Name: flagVariableName}
+			log.Debug(fmt.Sprintf("searching by filter: %v", searchFilterOpts))
+			items, total, err := camunda.SearchAll(cmd.Context(), svc.SearchVariablesPage, searchFilterOpts, flagPageSize, searchLimit())
+			if err != nil {
+				log.Error(fmt.Sprintf("error fetching variables: %v", err))
+				return
+			}
+			vars := varapi.Variables{Total: int32(total), Items: items}
+			logTruncated(cmd, "variables", len(vars.Items), vars.Total)
+			if flagKeysOnly {
+				err = listKeyOnlyVariablesView(cmd, vars)
+				if err != nil {
+					log.Error(fmt.Sprintf("error rendering keys-only view: %v", err))
+				}
+				return
+			}
+			if err = fetchFullVariableValues(cmd, svc, vars.Items); err != nil {
+				log.Error(fmt.Sprintf("error fetching full values of truncated variables: %v", err))
+				return
+			}
+			err = listVariablesView(cmd, vars)
+			if err != nil {
+				log.Error(fmt.Sprintf("error rendering items view: %v", err))
+			}
+
 		default:
 			log.Error(fmt.Sprintf("unknown resource type: %s, supported: %s", rn, supportedResourcesForGet))
 		}
@@ -209,12 +306,37 @@ func init() {
 	fs.BoolVar(&flagOrphanParentsOnly, "orphan-parents-only", false, "show only child instances whose parent does not exist (return 404 on get by key)")
 	fs.BoolVar(&flagIncidentsOnly, "incidents-only", false, "show only process instances that have incidents")
 	fs.BoolVar(&flagNoIncidentsOnly, "no-incidents-only", false, "show only process instances that have no incidents")
+	fs.Int64Var(&flagProcessInstanceKey, "process-instance-key", 0, "process instance key to filter incidents and variables")
+	fs.StringVar(&flagErrorType, "error-type", "", "error type to filter incidents, e.g. JOB_NO_RETRIES")
+	fs.Int64Var(&flagJobKey, "job-key", 0, "job key to filter incidents")
+	fs.StringVar(&flagVariableName, "name", "", "variable name to filter variables")
+
+	// paging options
+	fs.IntVar(&flagLimit, "limit", defaultSearchLimit, "maximum number of resources to fetch")
+	fs.BoolVar(&flagAll, "all", false, "fetch all matching resources, page by page (ignores --limit)")
+	fs.Int32Var(&flagPageSize, "page-size", defaultSearchPageSize, "number of resources fetched per request")
+	getCmd.MarkFlagsMutuallyExclusive("limit", "all")
 
 	// view options
 	fs.BoolVar(&flagKeysOnly, "keys-only", false, "show only keys in output")
 	fs.BoolVar(&flagOneLine, "one-line", false, "output one line per item")
 }
 
+// searchLimit returns the maximum number of items to fetch, 0 meaning all.
+func searchLimit() int {
+	if flagAll {
+		return 0
+	}
+	return flagLimit
+}
+
+// logTruncated tells the user when the server reported more matches than were fetched.
+func logTruncated(cmd *cobra.Command, what string, fetched int, total int32) {
+	if int(total) > fetched {
+		logging.FromContext(cmd.Context()).Info(fmt.Sprintf("fetched %d of %d %s, use --all or a higher --limit to fetch more", fetched, total, what))
+	}
+}
+
 func populatePISearchFilterOpts() piapi.SearchFilterOpts {
 	var opts piapi.SearchFilterOpts
 	if flagKey != 0 {
@@ -257,3 +379,18 @@ func populatePDSearchFilterOpts() pdapi.SearchFilterOpts {
 	}
 	return opts
 }
+
+// fetchFullVariableValues replaces truncated values found by search with the full values, fetched one by one.
+func fetchFullVariableValues(cmd *cobra.Command, svc varapi.API, items []varapi.Variable) error {
+	for i, v := range items {
+		if !v.Truncated {
+			continue
+		}
+		full, err := svc.GetVariableByKey(cmd.Context(), v.Key)
+		if err != nil {
+			return fmt.Errorf("variable %s (key %d): %w", v.Name, v.Key, err)
+		}
+		items[i] = full
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetVariable_ByProcessInstanceKey filters variables with the same --process-instance-key flag
+// that filters incidents.
+func TestGetVariable_ByProcessInstanceKey(t *testing.T) {
+	var searched string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/variables/search":
+			body, _ := io.ReadAll(r.Body)
+			searched = string(body)
+			_, _ = io.WriteString(w, `{"items":[{"variableKey":"2251799813685500","name":"approved","value":"true","processInstanceKey":"2251799813685251","scopeKey":"2251799813685251","isTruncated":false,"tenantId":"<default>"}],"page":{"totalItems":1}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	config := fmt.Sprintf(`auth:
+  mode: token
+  token:
+    value: test-token
+apis:
+  camunda_api:
+    base_url: %s/v2
+  operate_api:
+    base_url: %s
+`, srv.URL, srv.URL)
+
+	out, err := runWithConfig(t, config, "-a", "8.8", "get", "var", "--process-instance-key", "2251799813685251", "--keys-only")
+	require.NoError(t, err)
+	require.Contains(t, searched, `"processInstanceKey":"2251799813685251"`)
+	require.Contains(t, out, "2251799813685500")
+}
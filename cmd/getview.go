@@ -1,14 +1,93 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/grafvonb/camunder/pkg/camunda/cluster"
+	"github.com/grafvonb/camunder/pkg/camunda/incident"
 	"github.com/grafvonb/camunder/pkg/camunda/processdefinition"
 	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/grafvonb/camunder/pkg/camunda/variable"
 	"github.com/spf13/cobra"
 )
 
+// columns of the table, wide and csv output formats
+func init() {
+	registerColumns(
+		col("key", func(pi processinstance.ProcessInstance) string { return keyString(pi.Key) }),
+		wideCol("tenant", func(pi processinstance.ProcessInstance) string { return pi.TenantId }),
+		col("bpmn_process_id", func(pi processinstance.ProcessInstance) string { return pi.BpmnProcessId }),
+		col("version", func(pi processinstance.ProcessInstance) string { return fmt.Sprint(pi.ProcessVersion) }),
+		wideCol("version_tag", func(pi processinstance.ProcessInstance) string { return pi.ProcessVersionTag }),
+		col("state", func(pi processinstance.ProcessInstance) string { return string(pi.State) }),
+		col("start_date", func(pi processinstance.ProcessInstance) string { return pi.StartDate }),
+		wideCol("end_date", func(pi processinstance.ProcessInstance) string { return pi.EndDate }),
+		col("parent_key", func(pi processinstance.ProcessInstance) string { return keyString(pi.ParentKey) }),
+		wideCol("process_definition_key", func(pi processinstance.ProcessInstance) string { return keyString(pi.ProcessDefinitionKey) }),
+		col("incident", func(pi processinstance.ProcessInstance) string { return fmt.Sprint(pi.Incident) }),
+	)
+	registerColumns(
+		col("key", func(pd processdefinition.ProcessDefinition) string { return keyString(pd.Key) }),
+		wideCol("tenant", func(pd processdefinition.ProcessDefinition) string { return pd.TenantId }),
+		col("bpmn_process_id", func(pd processdefinition.ProcessDefinition) string { return pd.BpmnProcessId }),
+		col("name", func(pd processdefinition.ProcessDefinition) string { return pd.Name }),
+		col("version", func(pd processdefinition.ProcessDefinition) string { return fmt.Sprint(pd.Version) }),
+		wideCol("version_tag", func(pd processdefinition.ProcessDefinition) string { return pd.VersionTag }),
+	)
+	registerColumns(
+		col("key", func(inc incident.Incident) string { return keyString(inc.Key) }),
+		wideCol("tenant", func(inc incident.Incident) string { return inc.TenantId }),
+		col("process_instance_key", func(inc incident.Incident) string { return keyString(inc.ProcessInstanceKey) }),
+		wideCol("bpmn_process_id", func(inc incident.Incident) string { return inc.BpmnProcessId }),
+		col("state", func(inc incident.Incident) string { return string(inc.State) }),
+		col("error_type", func(inc incident.Incident) string { return inc.ErrorType }),
+		col("creation_time", func(inc incident.Incident) string { return inc.CreationTime }),
+		wideCol("job_key", func(inc incident.Incident) string { return keyString(inc.JobKey) }),
+		wideCol("error_message", func(inc incident.Incident) string { return inc.ErrorMessage }),
+	)
+	registerColumns(
+		col("key", func(v variable.Variable) string { return keyString(v.Key) }),
+		wideCol("tenant", func(v variable.Variable) string { return v.TenantId }),
+		col("process_instance_key", func(v variable.Variable) string { return keyString(v.ProcessInstanceKey) }),
+		wideCol("scope_key", func(v variable.Variable) string { return keyString(v.ScopeKey) }),
+		col("name", func(v variable.Variable) string { return v.Name }),
+		col("value", func(v variable.Variable) string { return scalarString(v.Value) }),
+		wideCol("truncated", func(v variable.Variable) string { return fmt.Sprint(v.Truncated) }),
+	)
+	registerColumns(
+		col("node_id", func(b cluster.Broker) string { return fmt.Sprint(b.NodeId) }),
+		col("host", func(b cluster.Broker) string { return b.Host }),
+		col("port", func(b cluster.Broker) string { return fmt.Sprint(b.Port) }),
+		col("version", func(b cluster.Broker) string { return b.Version }),
+		col("partitions", func(b cluster.Broker) string {
+			return partitionsString(b, func(p cluster.Partition) string { return string(p.Role) })
+		}),
+		wideCol("health", func(b cluster.Broker) string {
+			return partitionsString(b, func(p cluster.Partition) string { return string(p.Health) })
+		}),
+	)
+}
+
+// keyString formats a key, with an empty string for unset keys.
+func keyString(key int64) string {
+	if key == 0 {
+		return ""
+	}
+	return strconv.FormatInt(key, 10)
+}
+
+// partitionsString lists the partitions of a broker as id:value, e.g. 1:LEADER,2:FOLLOWER.
+func partitionsString(b cluster.Broker, value func(cluster.Partition) string) string {
+	parts := make([]string, len(b.Partitions))
+	for i, p := range b.Partitions {
+		parts[i] = fmt.Sprintf("%d:%s", p.PartitionId, value(p))
+	}
+	return strings.Join(parts, ",")
+}
+
 func listKeyOnlyProcessInstancesView(cmd *cobra.Command, resp processinstance.ProcessInstances) error {
 	return renderListViewV(cmd, resp, func(r processinstance.ProcessInstances) []processinstance.ProcessInstance {
 		return r.Items
@@ -16,6 +95,9 @@ func listKeyOnlyProcessInstancesView(cmd *cobra.Command, resp processinstance.Pr
 }
 
 func listProcessInstancesView(cmd *cobra.Command, resp processinstance.ProcessInstances) error {
+	if flagOutput != "" {
+		return renderOutput(cmd, resp, resp.Items)
+	}
 	if flagOneLine {
 		return renderListViewV(cmd, resp, func(r processinstance.ProcessInstances) []processinstance.ProcessInstance {
 			return r.Items
@@ -32,6 +114,9 @@ func keyOnlyProcessInstanceView(cmd *cobra.Command, item processinstance.Process
 }
 
 func processInstanceView(cmd *cobra.Command, item processinstance.ProcessInstance) error {
+	if flagOutput != "" {
+		return renderOutput(cmd, item, []processinstance.ProcessInstance{item})
+	}
 	if flagOneLine {
 		return oneLineProcessInstanceView(cmd, item)
 	}
@@ -43,6 +128,12 @@ func processInstanceView(cmd *cobra.Command, item processinstance.ProcessInstanc
 }
 
 func oneLineProcessInstanceView(cmd *cobra.Command, item processinstance.ProcessInstance) error {
+	cmd.Println(oneLineProcessInstance(item))
+	return nil
+}
+
+// oneLineProcessInstance is the one-line view of a process instance, shared by get and walk.
+func oneLineProcessInstance(item processinstance.ProcessInstance) string {
 	var pTag, eTag, vTag string
 	if item.ParentKey > 0 {
 		pTag = fmt.Sprintf(" p:%d", item.ParentKey)
@@ -60,7 +151,39 @@ func oneLineProcessInstanceView(cmd *cobra.Command, item processinstance.Process
 		"%-16d %s %s v%d%s %s s:%s%s%s i:%t",
 		item.Key, item.TenantId, item.BpmnProcessId, item.ProcessVersion, vTag, item.State, item.StartDate, eTag, pTag, item.Incident,
 	)
-	cmd.Println(strings.TrimSpace(out))
+	return strings.TrimSpace(out)
+}
+
+// createdProcessInstancesView renders started process instances like get pi; the JSON view
+// includes the variables returned when the completion was awaited.
+func createdProcessInstancesView(cmd *cobra.Command, items []processinstance.CreateResponse) error {
+	pis := make([]processinstance.ProcessInstance, 0, len(items))
+	for _, it := range items {
+		pis = append(pis, it.ProcessInstance)
+	}
+	switch {
+	case flagOutput != "" && len(items) == 1:
+		return renderOutput(cmd, items[0], pis)
+	case flagOutput != "":
+		return renderOutput(cmd, items, pis)
+	case flagKeysOnly:
+		return renderListViewV(cmd, pis, func(r []processinstance.ProcessInstance) []processinstance.ProcessInstance { return r }, keyOnlyProcessInstanceView)
+	case flagOneLine:
+		return renderListViewV(cmd, pis, func(r []processinstance.ProcessInstance) []processinstance.ProcessInstance { return r }, oneLineProcessInstanceView)
+	case len(items) == 1:
+		cmd.Println(ToJSONString(items[0]))
+		return nil
+	default:
+		return listJSONViewV(cmd, items, func(r []processinstance.CreateResponse) []processinstance.CreateResponse { return r })
+	}
+}
+
+// clusterTopologyView prints the topology as JSON; the row oriented output formats list its brokers.
+func clusterTopologyView(cmd *cobra.Command, topology cluster.Topology) error {
+	if flagOutput != "" {
+		return renderOutput(cmd, topology, topology.Brokers)
+	}
+	cmd.Println(ToJSONString(topology))
 	return nil
 }
 
@@ -71,6 +194,9 @@ func listKeyOnlyProcessDefinitionsView(cmd *cobra.Command, resp processdefinitio
 }
 
 func listProcessDefinitionsView(cmd *cobra.Command, resp processdefinition.ProcessDefinitions) error {
+	if flagOutput != "" {
+		return renderOutput(cmd, resp, resp.Items)
+	}
 	if flagOneLine {
 		return renderListViewV(cmd, resp, func(r processdefinition.ProcessDefinitions) []processdefinition.ProcessDefinition {
 			return r.Items
@@ -87,6 +213,9 @@ func keyOnlyProcessDefinitionView(cmd *cobra.Command, item processdefinition.Pro
 }
 
 func processDefinitionView(cmd *cobra.Command, item processdefinition.ProcessDefinition) error {
+	if flagOutput != "" {
+		return renderOutput(cmd, item, []processdefinition.ProcessDefinition{item})
+	}
 	if flagOneLine {
 		return oneLineProcessDefinitionView(cmd, item)
 	}
@@ -109,6 +238,106 @@ func oneLineProcessDefinitionView(cmd *cobra.Command, item processdefinition.Pro
 	return nil
 }
 
+func listKeyOnlyIncidentsView(cmd *cobra.Command, resp incident.Incidents) error {
+	return renderListViewV(cmd, resp, func(r incident.Incidents) []incident.Incident {
+		return r.Items
+	}, keyOnlyIncidentView)
+}
+
+func listIncidentsView(cmd *cobra.Command, resp incident.Incidents) error {
+	if flagOutput != "" {
+		return renderOutput(cmd, resp, resp.Items)
+	}
+	if flagOneLine {
+		return renderListViewV(cmd, resp, func(r incident.Incidents) []incident.Incident {
+			return r.Items
+		}, oneLineIncidentView)
+	}
+	return listJSONViewV(cmd, resp, func(r incident.Incidents) []incident.Incident {
+		return r.Items
+	})
+}
+
+func keyOnlyIncidentView(cmd *cobra.Command, item incident.Incident) error {
+	cmd.Println(item.Key)
+	return nil
+}
+
+func incidentView(cmd *cobra.Command, item incident.Incident) error {
+	if flagOutput != "" {
+		return renderOutput(cmd, item, []incident.Incident{item})
+	}
+	if flagOneLine {
+		return oneLineIncidentView(cmd, item)
+	}
+	if flagKeysOnly {
+		return keyOnlyIncidentView(cmd, item)
+	}
+	cmd.Println(ToJSONString(item))
+	return nil
+}
+
+func oneLineIncidentView(cmd *cobra.Command, item incident.Incident) error {
+	var jTag string
+	if item.JobKey > 0 {
+		jTag = fmt.Sprintf(" j:%d", item.JobKey)
+	}
+	out := fmt.Sprintf("%-16d %s pi:%d %s %s c:%s%s %q",
+		item.Key, item.TenantId, item.ProcessInstanceKey, item.State, item.ErrorType, item.CreationTime, jTag, item.ErrorMessage,
+	)
+	cmd.Println(strings.TrimSpace(out))
+	return nil
+}
+
+func listKeyOnlyVariablesView(cmd *cobra.Command, resp variable.Variables) error {
+	return renderListViewV(cmd, resp, func(r variable.Variables) []variable.Variable {
+		return r.Items
+	}, keyOnlyVariableView)
+}
+
+func listVariablesView(cmd *cobra.Command, resp variable.Variables) error {
+	if flagOutput != "" {
+		return renderOutput(cmd, resp, resp.Items)
+	}
+	if flagOneLine {
+		return renderListViewV(cmd, resp, func(r variable.Variables) []variable.Variable {
+			return r.Items
+		}, oneLineVariableView)
+	}
+	return listJSONViewV(cmd, resp, func(r variable.Variables) []variable.Variable {
+		return r.Items
+	})
+}
+
+func keyOnlyVariableView(cmd *cobra.Command, item variable.Variable) error {
+	cmd.Println(item.Key)
+	return nil
+}
+
+func variableView(cmd *cobra.Command, item variable.Variable) error {
+	if flagOutput != "" {
+		return renderOutput(cmd, item, []variable.Variable{item})
+	}
+	if flagOneLine {
+		return oneLineVariableView(cmd, item)
+	}
+	if flagKeysOnly {
+		return keyOnlyVariableView(cmd, item)
+	}
+	cmd.Println(ToJSONString(item))
+	return nil
+}
+
+func oneLineVariableView(cmd *cobra.Command, item variable.Variable) error {
+	value, err := json.Marshal(item.Value)
+	if err != nil {
+		return err
+	}
+	out := fmt.Sprintf("%-16d pi:%d sc:%d %s=%s", item.Key, item.ProcessInstanceKey, item.ScopeKey, item.Name, value)
+	cmd.Println(strings.TrimSpace(out))
+	return nil
+}
+
 //nolint:unused
 func listJSONView[Resp any, Item any](cmd *cobra.Command, resp *Resp, itemsOf func(*Resp) *[]Item) error {
 	if resp == nil {
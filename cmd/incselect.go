@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafvonb/camunder/pkg/camunda"
+	incapi "github.com/grafvonb/camunder/pkg/camunda/incident"
+	"github.com/spf13/cobra"
+)
+
+// incident filter options
+var (
+	flagProcessInstanceKey int64
+	flagErrorType          string
+	flagJobKey             int64
+)
+
+// incidentSelectionFlags are the flags that select incidents by search filter in commands acting on many incidents.
+var incidentSelectionFlags = []string{
+	"bpmn-process-id",
+	"process-instance-key",
+	"error-type",
+	"job-key",
+}
+
+// selectIncidents resolves the incidents a command acts on: the given keys as they are,
+// or all active incidents matching the search filter flags (fetched page by page).
+func selectIncidents(cmd *cobra.Command, svc incapi.API, keys []int64) ([]incapi.Incident, error) {
+	if len(keys) > 0 {
+		out := make([]incapi.Incident, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, incapi.Incident{Key: k})
+		}
+		return out, nil
+	}
+	if err := requireAnyFlag(cmd, append([]string{"key"}, incidentSelectionFlags...)...); err != nil {
+		return nil, err
+	}
+	filter := populateIncidentSearchFilterOpts()
+	filter.State = incapi.StateActive
+	incs, _, err := camunda.SearchAll(cmd.Context(), svc.SearchIncidentsPage, filter, defaultSearchPageSize, 0)
+	if err != nil {
+		return nil, fmt.Errorf("searching incidents: %w", err)
+	}
+	return incs, nil
+}
+
+func populateIncidentSearchFilterOpts() incapi.SearchFilterOpts {
+	return incapi.SearchFilterOpts{
+		Key:                flagKey,
+		ProcessInstanceKey: flagProcessInstanceKey,
+		JobKey:             flagJobKey,
+		BpmnProcessId:      flagBpmnProcessID,
+		ErrorType:          strings.ToUpper(flagErrorType),
+	}
+}
+
+func incidentKey(inc incapi.Incident) int64 { return inc.Key }
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// evalJSONPath selects values of v, the JSON form of a resource, by a JSONPath expression as known from
+// kubectl, e.g. {.items[*].key}. Braces and the leading $ are optional. Supported are child fields
+// (.name or ['name']), indexes ([0], [-1]) and wildcards ([*] or .*).
+func evalJSONPath(expr string, v any) ([]any, error) {
+	p := strings.TrimSpace(expr)
+	if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+		p = strings.TrimSpace(p[1 : len(p)-1])
+	}
+	p = strings.TrimPrefix(p, "$")
+
+	nodes := []any{v}
+	for p != "" {
+		var step func(any) []any
+		switch {
+		case strings.HasPrefix(p, "["):
+			end := strings.Index(p, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath %q: missing ]", expr)
+			}
+			sel := strings.TrimSpace(p[1:end])
+			p = p[end+1:]
+			switch {
+			case sel == "*":
+				step = pathChildren
+			case len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0]:
+				step = pathField(sel[1 : len(sel)-1])
+			default:
+				i, err := strconv.Atoi(sel)
+				if err != nil {
+					return nil, fmt.Errorf("jsonpath %q: invalid index %q", expr, sel)
+				}
+				step = pathIndex(i)
+			}
+		case strings.HasPrefix(p, "."):
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			name := p[:end]
+			p = p[end:]
+			switch name {
+			case "":
+				if p != "" {
+					return nil, fmt.Errorf("jsonpath %q: empty field name", expr)
+				}
+				step = func(n any) []any { return []any{n} } // "." selects the resource itself
+			case "*":
+				step = pathChildren
+			default:
+				step = pathField(name)
+			}
+		default:
+			return nil, fmt.Errorf("jsonpath %q: unexpected %q, expected . or [", expr, p)
+		}
+		var next []any
+		for _, n := range nodes {
+			next = append(next, step(n)...)
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+func pathField(name string) func(any) []any {
+	return func(n any) []any {
+		if m, ok := n.(map[string]any); ok {
+			if v, ok := m[name]; ok {
+				return []any{v}
+			}
+		}
+		return nil
+	}
+}
+
+func pathIndex(i int) func(any) []any {
+	return func(n any) []any {
+		s, ok := n.([]any)
+		if !ok {
+			return nil
+		}
+		j := i
+		if j < 0 {
+			j += len(s)
+		}
+		if j < 0 || j >= len(s) {
+			return nil
+		}
+		return []any{s[j]}
+	}
+}
+
+func pathChildren(n any) []any {
+	switch t := n.(type) {
+	case []any:
+		return t
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		out := make([]any, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, t[k])
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/grafvonb/camunder/internal/logging"
+	"github.com/grafvonb/camunder/internal/services/common"
+	"github.com/grafvonb/camunder/internal/services/processinstance"
+	piapi "github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+var supportedResourcesForMigrate = common.ResourceTypes{
+	"pi": "process-instance",
+}
+
+var (
+	flagMigrateKeys                []int64
+	flagMigrateTargetDefinitionKey int64
+	flagMigrateMappings            []string
+	flagMigratePlanFile            string
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate [resource name] [key]",
+	Short: "Migrate resources of a given type to another process definition by their keys or by search filter. " + supportedResourcesForMigrate.PrettyString(),
+	Long: "Migrate process instances to another process definition (version). Before anything is migrated, the active elements " +
+		"of every selected instance are checked against the mapping plan; if any active element has no mapping, nothing is migrated.",
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"m", "mig"},
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logging.FromContext(cmd.Context())
+		rn := strings.ToLower(args[0])
+		svcs, err := NewFromContext(cmd.Context())
+		if err != nil {
+			log.Error(fmt.Sprintf("%v", err))
+			return
+		}
+
+		switch rn {
+		case "process-instance", "pi":
+			plan, err := migrationPlan(flagMigratePlanFile, flagMigrateTargetDefinitionKey, flagMigrateMappings)
+			if err != nil {
+				log.Error(fmt.Sprintf("invalid migration plan: %v", err))
+				return
+			}
+			svc, err := processinstance.New(svcs.Config, svcs.HTTP.Client(), log)
+			if err != nil {
+				log.Error(fmt.Sprintf("creating process instance service: %v", err))
+				return
+			}
+			pis, err := selectProcessInstancesBy(cmd, svc, flagMigrateKeys, activePISearchFilterOpts(cmd))
+			if err != nil {
+				log.Error(fmt.Sprintf("selecting process instances: %v", err))
+				return
+			}
+			if len(pis) == 0 {
+				log.Info("no process instances selected, nothing to migrate")
+				return
+			}
+
+			log.Debug(fmt.Sprintf("checking the active elements of %d process instance(s) against the mapping plan", len(pis)))
+			checks := common.RunBulk(cmd.Context(), pis, flagParallel, func(ctx context.Context, pi piapi.ProcessInstance) error {
+				active, err := svc.GetActiveElementsOfProcessInstance(ctx, pi.Key)
+				if err != nil {
+					return fmt.Errorf("fetching active elements: %w", err)
+				}
+				if missing := plan.MissingMappings(active); len(missing) > 0 {
+					return fmt.Errorf("no mapping for active element(s): %s", strings.Join(missing, ", "))
+				}
+				return nil
+			})
+			if failed := countFailed(checks); failed > 0 || flagDryRun {
+				if failed > 0 {
+					bulkResultView(cmd, "check", checks, processInstanceKey)
+					log.Error(fmt.Sprintf("mapping plan check failed for %d of %d process instance(s), nothing was migrated", failed, len(checks)))
+					return
+				}
+				planned := make([]plannedAction, 0, len(pis))
+				for _, pi := range resolveProcessInstanceStates(cmd, svc, pis) {
+					planned = append(planned, plannedAction{Key: pi.Key, State: strings.ToUpper(pi.State.String()), Action: fmt.Sprintf("migrate to process definition %d", plan.TargetProcessDefinitionKey)})
+				}
+				dryRunView(cmd, "migrate", "process instance(s)", planned)
+				return
+			}
+
+			log.Debug(fmt.Sprintf("migrating %d process instance(s)", len(pis)))
+			results := common.RunBulk(cmd.Context(), pis, flagParallel, func(ctx context.Context, pi piapi.ProcessInstance) error {
+				_, err := svc.MigrateProcessInstance(ctx, pi.Key, plan)
+				return err
+			})
+			if failed := bulkResultView(cmd, "migrate", results, processInstanceKey); failed > 0 {
+				log.Error(fmt.Sprintf("migrating failed for %d of %d process instance(s)", failed, len(results)))
+			}
+		default:
+			log.Error(fmt.Sprintf("unknown resource type: %s, supported: %s", rn, supportedResourcesForMigrate))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	fs := migrateCmd.Flags()
+	fs.Int64SliceVarP(&flagMigrateKeys, "key", "k", nil, "resource key (e.g. process instance) to migrate (repeatable or comma-separated)")
+	AddProcessInstanceSelectionFlags(migrateCmd)
+
+	fs.Int64Var(&flagMigrateTargetDefinitionKey, "target-definition-key", 0, "key of the process definition to migrate to (overrides the plan file)")
+	fs.StringSliceVar(&flagMigrateMappings, "mapping", nil, "element mapping as source:target (repeatable or comma-separated, added to the plan file)")
+	fs.StringVar(&flagMigratePlanFile, "plan", "", "path to a YAML migration plan with targetProcessDefinitionKey and mappingInstructions")
+}
+
+// migrationPlan builds the plan from the optional YAML file and the flags.
+func migrationPlan(file string, targetKey int64, mappings []string) (piapi.MigrationPlan, error) {
+	var plan piapi.MigrationPlan
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return plan, err
+		}
+		if err = yaml.Unmarshal(data, &plan); err != nil {
+			return plan, fmt.Errorf("parsing %s: %w", file, err)
+		}
+	}
+	if targetKey > 0 {
+		plan.TargetProcessDefinitionKey = targetKey
+	}
+	for _, m := range mappings {
+		mi, err := piapi.ParseMapping(m)
+		if err != nil {
+			return plan, err
+		}
+		plan.MappingInstructions = append(plan.MappingInstructions, mi)
+	}
+	return plan, plan.Validate()
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestMigrate_DryRunShowsCurrentState plans the migration of an instance selected by key, which is
+// shown with the state it is in instead of an assumed one.
+func TestMigrate_DryRunShowsCurrentState(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/element-instances/search":
+			_, _ = io.WriteString(w, `{"items":[{"elementInstanceKey":"2251799813685300","processInstanceKey":"2251799813685251","elementId":"ship-order","state":"ACTIVE","type":"SERVICE_TASK"}],"page":{"totalItems":1}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/process-instances/2251799813685251":
+			_, _ = io.WriteString(w, `{"processInstanceKey":"2251799813685251","processDefinitionId":"order-process","state":"COMPLETED","hasIncident":false,"tenantId":"<default>"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	config := fmt.Sprintf(`auth:
+  mode: token
+  token:
+    value: test-token
+apis:
+  camunda_api:
+    base_url: %s/v2
+  operate_api:
+    base_url: %s
+`, srv.URL, srv.URL)
+
+	out, err := runWithConfig(t, config, "-a", "8.8", "--dry-run", "migrate", "pi", "--key", "2251799813685251",
+		"--target-definition-key", "2251799813685100", "--mapping", "ship-order:ship-order")
+	require.NoError(t, err)
+	require.Regexp(t, `2251799813685251 +COMPLETED +migrate to process definition 2251799813685100`, out)
+	require.Equal(t, "all", flagState, "the --state default is not overwritten")
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/grafvonb/camunder/internal/logging"
+	"github.com/grafvonb/camunder/internal/services/common"
+	"github.com/grafvonb/camunder/internal/services/processinstance"
+	"github.com/grafvonb/camunder/pkg/camunda"
+	piapi "github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/spf13/cobra"
+)
+
+var supportedResourcesForModify = common.ResourceTypes{
+	"pi": "process-instance",
+}
+
+var (
+	flagModifyKeys      []int64
+	flagModifyActivate  []string
+	flagModifyTerminate []int64
+	flagModifyMove      []string
+	flagModifyVariables string
+)
+
+// modifyCmd represents the modify command
+var modifyCmd = &cobra.Command{
+	Use:   "modify [resource name] [key]",
+	Short: "Modify resources of a given type, e.g. activate and terminate elements of process instances. " + supportedResourcesForModify.PrettyString(),
+	Long: "Modify process instances by activating elements (--activate elementId[:ancestorKey]), terminating element instances " +
+		"(--terminate elementInstanceKey) or moving tokens from one element to another (--move source:target). " +
+		"Process instances selected by search filter with only --move instructions are modified by one batch operation where the API supports it (8.8).",
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"mod"},
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logging.FromContext(cmd.Context())
+		rn := strings.ToLower(args[0])
+		svcs, err := NewFromContext(cmd.Context())
+		if err != nil {
+			log.Error(fmt.Sprintf("%v", err))
+			return
+		}
+
+		switch rn {
+		case "process-instance", "pi":
+			plan, moves, err := modificationPlan()
+			if err != nil {
+				log.Error(fmt.Sprintf("invalid modification: %v", err))
+				return
+			}
+			svc, err := processinstance.New(svcs.Config, svcs.HTTP.Client(), log)
+			if err != nil {
+				log.Error(fmt.Sprintf("creating process instance service: %v", err))
+				return
+			}
+			if useBatchModification(cmd, svc, plan, moves) {
+				modifyProcessInstancesInBatch(cmd, svc, moves)
+				return
+			}
+
+			pis, err := selectProcessInstancesBy(cmd, svc, flagModifyKeys, activePISearchFilterOpts(cmd))
+			if err != nil {
+				log.Error(fmt.Sprintf("selecting process instances: %v", err))
+				return
+			}
+			if len(pis) == 0 {
+				log.Info("no process instances selected, nothing to modify")
+				return
+			}
+			if len(pis) > 1 && (len(plan.Terminate) > 0 || hasAncestorKeys(plan)) {
+				log.Error("element instance keys of --terminate and --activate belong to a single process instance, select exactly one")
+				return
+			}
+
+			// resolve the moves against the active elements of each instance
+			plans := make(map[int64]piapi.ModificationPlan, len(pis))
+			if len(moves) > 0 {
+				var mu sync.Mutex
+				resolved := common.RunBulk(cmd.Context(), pis, flagParallel, func(ctx context.Context, pi piapi.ProcessInstance) error {
+					active, err := svc.GetActiveElementsOfProcessInstance(ctx, pi.Key)
+					if err != nil {
+						return fmt.Errorf("fetching active elements: %w", err)
+					}
+					p := plan.WithMoves(moves, active)
+					if err = p.Validate(); err != nil {
+						return fmt.Errorf("nothing to modify, none of the source elements is active: %w", err)
+					}
+					mu.Lock()
+					plans[pi.Key] = p
+					mu.Unlock()
+					return nil
+				})
+				if failed := countFailed(resolved); failed > 0 {
+					bulkResultView(cmd, "check", resolved, processInstanceKey)
+					log.Error(fmt.Sprintf("resolving the moves failed for %d of %d process instance(s), nothing was modified", failed, len(resolved)))
+					return
+				}
+			} else {
+				for _, pi := range pis {
+					plans[pi.Key] = plan
+				}
+			}
+
+			if flagDryRun {
+				planned := make([]plannedAction, 0, len(pis))
+				for _, pi := range resolveProcessInstanceStates(cmd, svc, pis) {
+					planned = append(planned, plannedAction{Key: pi.Key, State: strings.ToUpper(pi.State.String()), Action: describeModification(plans[pi.Key])})
+				}
+				dryRunView(cmd, "modify", "process instance(s)", planned)
+				return
+			}
+
+			log.Debug(fmt.Sprintf("modifying %d process instance(s)", len(pis)))
+			results := common.RunBulk(cmd.Context(), pis, flagParallel, func(ctx context.Context, pi piapi.ProcessInstance) error {
+				_, err := svc.ModifyProcessInstance(ctx, pi.Key, plans[pi.Key])
+				return err
+			})
+			if failed := bulkResultView(cmd, "modify", results, processInstanceKey); failed > 0 {
+				log.Error(fmt.Sprintf("modifying failed for %d of %d process instance(s)", failed, len(results)))
+			}
+		default:
+			log.Error(fmt.Sprintf("unknown resource type: %s, supported: %s", rn, supportedResourcesForModify))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(modifyCmd)
+
+	fs := modifyCmd.Flags()
+	fs.Int64SliceVarP(&flagModifyKeys, "key", "k", nil, "resource key (e.g. process instance) to modify (repeatable or comma-separated)")
+	AddProcessInstanceSelectionFlags(modifyCmd)
+
+	fs.StringSliceVar(&flagModifyActivate, "activate", nil, "element to activate as elementId[:ancestorElementInstanceKey] (repeatable or comma-separated)")
+	fs.Int64SliceVar(&flagModifyTerminate, "terminate", nil, "key of an element instance to terminate (repeatable or comma-separated)")
+	fs.StringSliceVar(&flagModifyMove, "move", nil, "move tokens from the source to the target element as source:target (repeatable or comma-separated)")
+	fs.StringVar(&flagModifyVariables, "variables", "", "variables created with the first activated element, as JSON object or @path to a file with the JSON object")
+}
+
+// useBatchModification reports whether the instances selected by search filter can be modified with one
+// batch operation: it needs 8.8, only --move instructions and no client-side filters.
+func useBatchModification(cmd *cobra.Command, svc piapi.API, plan piapi.ModificationPlan, moves []piapi.MoveInstruction) bool {
+	return len(flagModifyKeys) == 0 && len(moves) > 0 &&
+		len(plan.Activate) == 0 && len(plan.Terminate) == 0 && len(plan.Variables) == 0 &&
+		!flagIncidentsOnly && !flagOrphanParentsOnly &&
+		svc.Capabilities(cmd.Context()).APIVersion != camunda.V87
+}
+
+// modifyProcessInstancesInBatch moves the tokens of all instances matching the filter flags with one batch operation.
+func modifyProcessInstancesInBatch(cmd *cobra.Command, svc piapi.API, moves []piapi.MoveInstruction) {
+	log := logging.FromContext(cmd.Context())
+	if err := requireAnyFlag(cmd, append([]string{"key"}, processInstanceSelectionFlags...)...); err != nil {
+		log.Error(fmt.Sprintf("selecting process instances: %v", err))
+		return
+	}
+	filter := activePISearchFilterOpts(cmd)
+	if flagDryRun {
+		pis, err := searchProcessInstances(cmd, svc, filter, defaultSearchPageSize, 0)
+		if err != nil {
+			log.Error(fmt.Sprintf("selecting process instances: %v", err))
+			return
+		}
+		action := describeMoves(moves) + " (batch operation)"
+		planned := make([]plannedAction, 0, len(pis.Items))
+		for _, pi := range pis.Items {
+			planned = append(planned, plannedAction{Key: pi.Key, State: strings.ToUpper(pi.State.String()), Action: action})
+		}
+		dryRunView(cmd, "modify", "process instance(s)", planned)
+		return
+	}
+	op, err := svc.ModifyProcessInstances(cmd.Context(), filter, moves)
+	if err != nil {
+		log.Error(fmt.Sprintf("creating batch operation to modify process instances: %v", err))
+		return
+	}
+	cmd.Println(fmt.Sprintf("batch operation %s (%s) created, the engine modifies the matching process instances asynchronously", op.Key, op.Type))
+}
+
+// modificationPlan builds the plan shared by all selected instances and the moves resolved per instance.
+func modificationPlan() (piapi.ModificationPlan, []piapi.MoveInstruction, error) {
+	var plan piapi.ModificationPlan
+	for _, a := range flagModifyActivate {
+		ai, err := piapi.ParseActivate(a)
+		if err != nil {
+			return plan, nil, err
+		}
+		plan.Activate = append(plan.Activate, ai)
+	}
+	plan.Terminate = flagModifyTerminate
+	var moves []piapi.MoveInstruction
+	for _, m := range flagModifyMove {
+		mi, err := piapi.ParseMove(m)
+		if err != nil {
+			return plan, nil, err
+		}
+		moves = append(moves, mi)
+	}
+	vars, err := variablesArg(flagModifyVariables)
+	if err != nil {
+		return plan, nil, fmt.Errorf("reading variables: %w", err)
+	}
+	plan.Variables = vars
+	if len(moves) == 0 {
+		return plan, nil, plan.Validate()
+	}
+	// with moves the plan is complete only after resolving them per instance
+	return plan, moves, nil
+}
+
+func hasAncestorKeys(plan piapi.ModificationPlan) bool {
+	for _, a := range plan.Activate {
+		if a.AncestorElementInstanceKey > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// describeModification renders a plan for the dry-run output.
+func describeModification(plan piapi.ModificationPlan) string {
+	var parts []string
+	for _, a := range plan.Activate {
+		if a.AncestorElementInstanceKey > 0 {
+			parts = append(parts, fmt.Sprintf("activate %s in %d", a.ElementId, a.AncestorElementInstanceKey))
+			continue
+		}
+		parts = append(parts, "activate "+a.ElementId)
+	}
+	for _, k := range plan.Terminate {
+		parts = append(parts, fmt.Sprintf("terminate %d", k))
+	}
+	if len(plan.Variables) > 0 {
+		parts = append(parts, fmt.Sprintf("set %d variable(s)", len(plan.Variables)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func describeMoves(moves []piapi.MoveInstruction) string {
+	parts := make([]string, 0, len(moves))
+	for _, m := range moves {
+		parts = append(parts, fmt.Sprintf("move %s -> %s", m.SourceElementId, m.TargetElementId))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestModify_DryRunShowsCurrentState plans the modification of an instance selected by key, which is
+// shown with the state it is in instead of an assumed one.
+func TestModify_DryRunShowsCurrentState(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/process-instances/2251799813685251":
+			_, _ = io.WriteString(w, `{"processInstanceKey":"2251799813685251","processDefinitionId":"order-process","state":"CANCELED","hasIncident":false,"tenantId":"<default>"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	config := fmt.Sprintf(`auth:
+  mode: token
+  token:
+    value: test-token
+apis:
+  camunda_api:
+    base_url: %s/v2
+  operate_api:
+    base_url: %s
+`, srv.URL, srv.URL)
+
+	out, err := runWithConfig(t, config, "-a", "8.8", "--dry-run", "modify", "pi", "--key", "2251799813685251", "--terminate", "2251799813685300")
+	require.NoError(t, err)
+	require.Regexp(t, `2251799813685251 +CANCELED +terminate`, out)
+	require.Equal(t, "all", flagState, "the --state default is not overwritten")
+}
@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+// flagOutput selects the output format of the commands printing resources; empty keeps their text views.
+var flagOutput string
+
+// outputFormat is a parsed --output value, e.g. template={{.key}}: the format name and its argument.
+type outputFormat struct {
+	name string
+	arg  string
+}
+
+// outputRenderer writes a resource in one format. whole is the resource as fetched, e.g. a search result
+// with its total; rows are its items, printed one per line or row by the row oriented formats.
+type outputRenderer func(w io.Writer, arg string, whole any, rows []any, cols []column) error
+
+var outputRenderers = map[string]outputRenderer{
+	"json":     renderJSON,
+	"yaml":     renderYAML,
+	"table":    tableRenderer(false),
+	"wide":     tableRenderer(true),
+	"csv":      renderCSV,
+	"ndjson":   renderNDJSON,
+	"template": renderTemplate,
+	"jsonpath": renderJSONPath,
+}
+
+// outputFormatsWithArg need the argument after =, the others take none.
+var outputFormatsWithArg = map[string]bool{"template": true, "jsonpath": true}
+
+func outputFormatNames() []string {
+	names := make([]string, 0, len(outputRenderers))
+	for name := range outputRenderers {
+		if outputFormatsWithArg[name] {
+			name += "=..."
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func parseOutputFormat(s string) (outputFormat, error) {
+	name, arg, hasArg := strings.Cut(s, "=")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, ok := outputRenderers[name]; !ok {
+		return outputFormat{}, fmt.Errorf("invalid output format %q, supported: %s", s, strings.Join(outputFormatNames(), ", "))
+	}
+	switch {
+	case outputFormatsWithArg[name] && strings.TrimSpace(arg) == "":
+		return outputFormat{}, fmt.Errorf("output format %s needs an expression, e.g. %s=...", name, name)
+	case !outputFormatsWithArg[name] && hasArg:
+		return outputFormat{}, fmt.Errorf("output format %s takes no expression", name)
+	}
+	return outputFormat{name: name, arg: arg}, nil
+}
+
+// validateOutputFlags rejects an invalid --output before anything is fetched.
+func validateOutputFlags(cmd *cobra.Command) error {
+	if flagOutput == "" {
+		return nil
+	}
+	if _, err := parseOutputFormat(flagOutput); err != nil {
+		return err
+	}
+	for _, name := range []string{"keys-only", "one-line"} {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			return fmt.Errorf("--output cannot be combined with --%s", name)
+		}
+	}
+	return nil
+}
+
+// renderOutput writes the resource to standard output in the format selected by --output.
+// Single resources are passed with themselves as the only row.
+func renderOutput[T any](cmd *cobra.Command, whole any, items []T) error {
+	f, err := parseOutputFormat(flagOutput)
+	if err != nil {
+		return err
+	}
+	rows := make([]any, len(items))
+	for i, it := range items {
+		rows[i] = it
+	}
+	return outputRenderers[f.name](cmd.OutOrStdout(), f.arg, whole, rows, columnsOf[T]())
+}
+
+func renderJSON(w io.Writer, _ string, whole any, _ []any, _ []column) error {
+	return JSON(w, whole)
+}
+
+func renderYAML(w io.Writer, _ string, whole any, _ []any, _ []column) error {
+	v, err := toGeneric(whole)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err = enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func renderNDJSON(w io.Writer, _ string, _ any, rows []any, _ []column) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, r := range rows {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tableRenderer aligns the columns like kubectl; wide adds the columns marked as wide.
+func tableRenderer(wide bool) outputRenderer {
+	return func(w io.Writer, _ string, _ any, rows []any, cols []column) error {
+		if len(rows) == 0 {
+			return nil
+		}
+		if !wide {
+			cols = slices.DeleteFunc(slices.Clone(cols), func(c column) bool { return c.wide })
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		header := make([]string, len(cols))
+		for i, c := range cols {
+			header[i] = strings.ToUpper(strings.ReplaceAll(c.name, "_", " "))
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, r := range rows {
+			cells := make([]string, len(cols))
+			for i, c := range cols {
+				cells[i] = orDash(strings.NewReplacer("\t", " ", "\n", " ").Replace(c.value(r)))
+			}
+			_, _ = fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+// renderCSV writes all columns, including the wide ones, with the column names as header.
+func renderCSV(w io.Writer, _ string, _ any, rows []any, cols []column) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		record := make([]string, len(cols))
+		for i, c := range cols {
+			record[i] = c.value(r)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// renderTemplate executes a Go template on the resource as it appears in JSON, so fields are
+// addressed by their JSON names, e.g. {{range .items}}{{.key}}{{"\n"}}{{end}}.
+func renderTemplate(w io.Writer, arg string, whole any, _ []any, _ []column) error {
+	t, err := template.New("output").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(arg)
+	if err != nil {
+		return fmt.Errorf("parse template: %w", err)
+	}
+	v, err := toGeneric(whole)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, v)
+}
+
+// renderJSONPath prints the values selected by the expression, separated by spaces, see evalJSONPath.
+func renderJSONPath(w io.Writer, arg string, whole any, _ []any, _ []column) error {
+	v, err := toGeneric(whole)
+	if err != nil {
+		return err
+	}
+	values, err := evalJSONPath(arg, v)
+	if err != nil {
+		return err
+	}
+	out := make([]string, len(values))
+	for i, val := range values {
+		out[i] = scalarString(val)
+	}
+	_, err = fmt.Fprintln(w, strings.Join(out, " "))
+	return err
+}
+
+// toGeneric converts v to its JSON form of maps, slices and scalars. Integers stay int64, so keys
+// above 2^53 are not rounded as float64.
+func toGeneric(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var out any
+	if err = dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return fromNumbers(out), nil
+}
+
+func fromNumbers(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			t[k] = fromNumbers(e)
+		}
+	case []any:
+		for i, e := range t {
+			t[i] = fromNumbers(e)
+		}
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	}
+	return v
+}
+
+// scalarString formats strings and numbers as they are and everything else as compact JSON.
+func scalarString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case fmt.Stringer:
+		return t.String()
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Pointer, reflect.Interface:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// column is a column of the table, wide and csv formats.
+type column struct {
+	name  string // csv header, upper-cased as table header
+	wide  bool   // shown by wide and csv only
+	value func(any) string
+}
+
+// typedColumn is a column as registered for a resource type, see registerColumns.
+type typedColumn[T any] struct {
+	name  string
+	wide  bool
+	value func(T) string
+}
+
+func col[T any](name string, value func(T) string) typedColumn[T] {
+	return typedColumn[T]{name: name, value: value}
+}
+
+func wideCol[T any](name string, value func(T) string) typedColumn[T] {
+	return typedColumn[T]{name: name, wide: true, value: value}
+}
+
+// outputColumns holds the registered columns by resource type, other types get a column per field.
+var outputColumns = map[reflect.Type][]column{}
+
+func registerColumns[T any](cols ...typedColumn[T]) {
+	erased := make([]column, len(cols))
+	for i, c := range cols {
+		erased[i] = column{name: c.name, wide: c.wide, value: func(v any) string { return c.value(v.(T)) }}
+	}
+	outputColumns[reflect.TypeFor[T]()] = erased
+}
+
+func columnsOf[T any]() []column {
+	t := reflect.TypeFor[T]()
+	if cols, ok := outputColumns[t]; ok {
+		return cols
+	}
+	return fieldColumns(t)
+}
+
+// fieldColumns derives a column per exported field, named like in JSON, for resources without registered columns.
+func fieldColumns(t reflect.Type) []column {
+	if t.Kind() != reflect.Struct {
+		return []column{{name: "value", value: scalarString}}
+	}
+	var cols []column
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for _, f := range reflect.VisibleFields(t) {
+			if len(f.Index) != 1 || !f.IsExported() {
+				continue
+			}
+			idx := append(slices.Clone(index), f.Index[0])
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if f.Anonymous && f.Type.Kind() == reflect.Struct && name == "" {
+				walk(f.Type, idx)
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			cols = append(cols, column{name: name, value: func(v any) string {
+				return scalarString(reflect.ValueOf(v).FieldByIndex(idx).Interface())
+			}})
+		}
+	}
+	walk(t, nil)
+	return cols
+}
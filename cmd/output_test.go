@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+var outputTestItems = processinstance.ProcessInstances{
+	Total: 2,
+	Items: []processinstance.ProcessInstance{
+		{Key: 2251799813685251, BpmnProcessId: "order-process", ProcessVersion: 3, State: "ACTIVE", TenantId: "<default>"},
+		{Key: 2251799813685260, BpmnProcessId: "order, \"express\"", ProcessVersion: 1, State: "COMPLETED", ParentKey: 2251799813685251},
+	},
+}
+
+func renderTestOutput(t *testing.T, format string, whole any, items []processinstance.ProcessInstance) string {
+	t.Helper()
+	flagOutput = format
+	t.Cleanup(func() { flagOutput = "" })
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	require.NoError(t, renderOutput(cmd, whole, items))
+	return out.String()
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	f, err := parseOutputFormat("template={{.key}}={{.state}}")
+	require.NoError(t, err)
+	require.Equal(t, outputFormat{name: "template", arg: "{{.key}}={{.state}}"}, f)
+
+	f, err = parseOutputFormat("YAML")
+	require.NoError(t, err)
+	require.Equal(t, "yaml", f.name)
+
+	for _, invalid := range []string{"xml", "jsonpath", "template=", "json=.items"} {
+		_, err = parseOutputFormat(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestRenderOutput_Formats(t *testing.T) {
+	items := outputTestItems.Items
+
+	out := renderTestOutput(t, "table", outputTestItems, items)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	require.Regexp(t, `^KEY\s+BPMN PROCESS ID\s+VERSION\s+STATE\s+START DATE\s+PARENT KEY\s+INCIDENT$`, lines[0])
+	require.Regexp(t, `^2251799813685251\s+order-process\s+3\s+ACTIVE\s+-\s+-\s+false$`, lines[1])
+
+	out = renderTestOutput(t, "wide", outputTestItems, items)
+	require.Contains(t, out, "TENANT")
+	require.Contains(t, out, "<default>")
+
+	out = renderTestOutput(t, "csv", outputTestItems, items)
+	require.Equal(t, "key,tenant,bpmn_process_id,version,version_tag,state,start_date,end_date,parent_key,process_definition_key,incident\n"+
+		"2251799813685251,<default>,order-process,3,,ACTIVE,,,,,false\n"+
+		"2251799813685260,,\"order, \"\"express\"\"\",1,,COMPLETED,,,2251799813685251,,false\n", out)
+
+	out = renderTestOutput(t, "ndjson", outputTestItems, items)
+	require.Equal(t, `{"bpmnProcessId":"order-process","key":2251799813685251,"processVersion":3,"state":"ACTIVE","tenantId":"<default>"}`+"\n"+
+		`{"bpmnProcessId":"order, \"express\"","key":2251799813685260,"parentKey":2251799813685251,"processVersion":1,"state":"COMPLETED"}`+"\n", out)
+
+	out = renderTestOutput(t, "yaml", outputTestItems, items)
+	require.Contains(t, out, "total: 2\n")
+	require.Contains(t, out, "  - bpmnProcessId: order-process\n    key: 2251799813685251\n")
+
+	out = renderTestOutput(t, "json", items[0], items[:1])
+	require.JSONEq(t, `{"bpmnProcessId":"order-process","key":2251799813685251,"processVersion":3,"state":"ACTIVE","tenantId":"<default>"}`, out)
+
+	out = renderTestOutput(t, `template={{range .items}}{{.key}}:{{.state}} {{end}}`, outputTestItems, items)
+	require.Equal(t, "2251799813685251:ACTIVE 2251799813685260:COMPLETED ", out, "keys are printed as integers")
+
+	out = renderTestOutput(t, "jsonpath={.items[*].key}", outputTestItems, items)
+	require.Equal(t, "2251799813685251 2251799813685260\n", out)
+}
+
+func TestRenderOutput_FieldColumns(t *testing.T) {
+	type entry struct {
+		Key       int64             `json:"key"`
+		ElementId string            `json:"elementId"`
+		Skipped   string            `json:"-"`
+		Labels    map[string]string `json:"labels"`
+	}
+	flagOutput = "csv"
+	t.Cleanup(func() { flagOutput = "" })
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	items := []entry{{Key: 1, ElementId: "start", Skipped: "x", Labels: map[string]string{"a": "b"}}}
+	require.NoError(t, renderOutput(cmd, items, items))
+	require.Equal(t, "key,elementId,labels\n1,start,\"{\"\"a\"\":\"\"b\"\"}\"\n", out.String())
+}
+
+func TestEvalJSONPath(t *testing.T) {
+	v, err := toGeneric(outputTestItems)
+	require.NoError(t, err)
+
+	cases := map[string][]any{
+		"{.total}":                   {int64(2)},
+		"$.items[0].bpmnProcessId":   {"order-process"},
+		".items[-1].key":             {int64(2251799813685260)},
+		"{.items[*].state}":          {"ACTIVE", "COMPLETED"},
+		"{.items[*]['parentKey']}":   {int64(2251799813685251)},
+		"{.items[5].key}":            nil,
+		"{.items[0].missing.nested}": nil,
+	}
+	for expr, want := range cases {
+		got, err := evalJSONPath(expr, v)
+		require.NoError(t, err, expr)
+		require.Equal(t, want, got, expr)
+	}
+
+	for _, invalid := range []string{"{.items[x]}", "{.items[0}", "items"} {
+		_, err = evalJSONPath(invalid, v)
+		require.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grafvonb/camunder/pkg/camunda"
+	piapi "github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/spf13/cobra"
+)
+
+// bulk options
+var (
+	flagParallel int
+)
+
+// processInstanceSelectionFlags are the flags that select process instances by search filter
+// in commands acting on many instances (cancel, delete).
+var processInstanceSelectionFlags = []string{
+	"bpmn-process-id",
+	"process-version",
+	"state",
+	"parent-key",
+	"incidents-only",
+	"orphan-parents-only",
+}
+
+// selectProcessInstances resolves the process instances a command acts on: the given keys as they are,
+// or all instances matching the search filter flags (fetched page by page) with the post filters applied.
+func selectProcessInstances(cmd *cobra.Command, svc piapi.API, keys []int64) ([]piapi.ProcessInstance, error) {
+	return selectProcessInstancesBy(cmd, svc, keys, populatePISearchFilterOpts())
+}
+
+// selectProcessInstancesBy is selectProcessInstances with the search filter given instead of read from the flags.
+func selectProcessInstancesBy(cmd *cobra.Command, svc piapi.API, keys []int64, filter piapi.SearchFilterOpts) ([]piapi.ProcessInstance, error) {
+	if len(keys) > 0 {
+		out := make([]piapi.ProcessInstance, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, piapi.ProcessInstance{Key: k})
+		}
+		return out, nil
+	}
+	if err := requireAnyFlag(cmd, append([]string{"key"}, processInstanceSelectionFlags...)...); err != nil {
+		return nil, err
+	}
+	pisr, err := searchProcessInstances(cmd, svc, filter, defaultSearchPageSize, 0)
+	if err != nil {
+		return nil, err
+	}
+	return pisr.Items, nil
+}
+
+// searchProcessInstances runs the paged search and applies the client-side filters set by flags.
+func searchProcessInstances(cmd *cobra.Command, svc piapi.API, filter piapi.SearchFilterOpts, pageSize int32, limit int) (piapi.ProcessInstances, error) {
+	if flagChildrenOnly && flagParentsOnly {
+		return piapi.ProcessInstances{}, errors.New("using both --children-only and --parents-only filters returns always no results")
+	}
+	ctx := cmd.Context()
+	items, total, err := camunda.SearchAll(ctx, svc.SearchForProcessInstancesPage, filter, pageSize, limit)
+	if err != nil {
+		return piapi.ProcessInstances{}, fmt.Errorf("searching process instances: %w", err)
+	}
+	pisr := piapi.ProcessInstances{Total: int32(total), Items: items}
+	logTruncated(cmd, "process instances", len(pisr.Items), pisr.Total)
+	if flagChildrenOnly {
+		pisr = pisr.FilterChildrenOnly()
+	}
+	if flagParentsOnly {
+		pisr = pisr.FilterParentsOnly()
+	}
+	if flagOrphanParentsOnly {
+		pisr.Items, err = svc.FilterProcessInstanceWithOrphanParent(ctx, pisr.Items)
+		if err != nil {
+			return piapi.ProcessInstances{}, fmt.Errorf("filtering orphan parents: %w", err)
+		}
+		pisr.Total = int32(len(pisr.Items))
+	}
+	if flagIncidentsOnly {
+		pisr = pisr.FilterByHavingIncidents(true)
+	}
+	if flagNoIncidentsOnly {
+		pisr = pisr.FilterByHavingIncidents(false)
+	}
+	return pisr, nil
+}
+
+// activePISearchFilterOpts is the search filter of commands that act on active process instances
+// only: the state is active unless --state is given.
+func activePISearchFilterOpts(cmd *cobra.Command) piapi.SearchFilterOpts {
+	filter := populatePISearchFilterOpts()
+	if !cmd.Flags().Changed("state") {
+		filter.State = piapi.StateActive
+	}
+	return filter
+}
+
+func processInstanceKey(pi piapi.ProcessInstance) int64 { return pi.Key }
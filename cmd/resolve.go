@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grafvonb/camunder/internal/api/convert"
+	"github.com/grafvonb/camunder/internal/logging"
+	"github.com/grafvonb/camunder/internal/services/common"
+	"github.com/grafvonb/camunder/internal/services/incident"
+	incapi "github.com/grafvonb/camunder/pkg/camunda/incident"
+	"github.com/spf13/cobra"
+)
+
+var supportedResourcesForResolve = common.ResourceTypes{
+	"inc": "incident",
+}
+
+var (
+	flagResolveKeys []int64
+)
+
+// resolveCmd represents the resolve command
+var resolveCmd = &cobra.Command{
+	Use:     "resolve [resource name] [key]",
+	Short:   "Resolve resources of a given type by their keys or by search filter. " + supportedResourcesForResolve.PrettyString(),
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"r", "rs", "fix"},
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logging.FromContext(cmd.Context())
+		rn := strings.ToLower(args[0])
+		svcs, err := NewFromContext(cmd.Context())
+		if err != nil {
+			log.Error(fmt.Sprintf("%v", err))
+			return
+		}
+
+		switch rn {
+		case "incident", "inc":
+			svc, err := incident.New(svcs.Config, svcs.HTTP.Client(), log)
+			if err != nil {
+				log.Error(fmt.Sprintf("creating incident service: %v", err))
+				return
+			}
+			incs, err := selectIncidents(cmd, svc, flagResolveKeys)
+			if err != nil {
+				log.Error(fmt.Sprintf("selecting incidents: %v", err))
+				return
+			}
+			if len(incs) == 0 {
+				log.Info("no active incidents selected, nothing to resolve")
+				return
+			}
+			if flagDryRun {
+				planned := convert.MapSlice(resolveIncidentStates(cmd, svc, incs), planResolve)
+				dryRunView(cmd, "resolve", "incident(s)", planned)
+				return
+			}
+			log.Debug(fmt.Sprintf("resolving %d incident(s)", len(incs)))
+			results := common.RunBulk(cmd.Context(), incs, flagParallel, func(ctx context.Context, inc incapi.Incident) error {
+				_, err := svc.ResolveIncident(ctx, inc.Key)
+				return err
+			})
+			if failed := bulkResultView(cmd, "resolve", results, incidentKey); failed > 0 {
+				log.Error(fmt.Sprintf("resolving failed for %d of %d incident(s)", failed, len(results)))
+			}
+		default:
+			log.Error(fmt.Sprintf("unknown resource type: %s, supported: %s", rn, supportedResourcesForResolve))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resolveCmd)
+
+	resolveCmd.Flags().Int64SliceVarP(&flagResolveKeys, "key", "k", nil, "resource key (e.g. incident) to resolve (repeatable or comma-separated)")
+	AddIncidentSelectionFlags(resolveCmd)
+}
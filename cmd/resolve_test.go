@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestResolve_DryRunMissingIncident plans the resolution of an incident key the API does not know,
+// which is shown as not found instead of as an unknown state.
+func TestResolve_DryRunMissingIncident(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"title":"NOT_FOUND","status":404,"detail":"Incident with key '2251799813685400' not found"}`)
+	}))
+	t.Cleanup(srv.Close)
+	config := fmt.Sprintf(`auth:
+  mode: token
+  token:
+    value: test-token
+apis:
+  camunda_api:
+    base_url: %s/v2
+  operate_api:
+    base_url: %s
+`, srv.URL, srv.URL)
+
+	out, err := runWithConfig(t, config, "-a", "8.8", "--dry-run", "resolve", "incident", "--key", "2251799813685400")
+	require.NoError(t, err)
+	require.Regexp(t, `2251799813685400 +NOT_FOUND +resolve \(expected to fail: not found\)`, out)
+}
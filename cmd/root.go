@@ -18,33 +18,62 @@ import (
 )
 
 var (
-	flagShowConfig bool // show effective config and exit
+	flagDryRun bool // resolve targets and report planned actions without changing anything
 )
 
+// flagBindings maps config keys to the persistent flags that set them.
+var flagBindings = map[string]string{
+	"config":                     "config",
+	"profile":                    "profile",
+	"log.level":                  "log-level",
+	"log.format":                 "log-format",
+	"log.with_source":            "log-with-source",
+	"app.tenant":                 "tenant",
+	"auth.oauth2.token_url":      "auth-token-url",
+	"auth.oauth2.client_id":      "auth-client-id",
+	"auth.oauth2.client_secret":  "auth-client-secret",
+	"auth.token_cache.enabled":   "auth-token-cache",
+	"http.timeout":               "http-timeout",
+	"http.record":                "record",
+	"http.replay":                "replay",
+	"apis.version":               "camunda-apis-version",
+	"apis.camunda_api.base_url":  "camunda-base-url",
+	"apis.operate_api.base_url":  "operate-base-url",
+	"apis.tasklist_api.base_url": "tasklist-base-url",
+	"tmp.auth_scopes":            "auth-scopes",
+	// registered by the commands that poll, see AddBackoffFlagsAndBindings
+	"app.backoff.strategy":      "backoff-strategy",
+	"app.backoff.initial_delay": "backoff-initial-delay",
+	"app.backoff.max_delay":     "backoff-max-delay",
+	"app.backoff.max_retries":   "backoff-max-retries",
+	"app.backoff.multiplier":    "backoff-multiplier",
+	"app.backoff.timeout":       "backoff-timeout",
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "camunder",
 	Short: "Camunder is a CLI tool to interact with Camunda 8.",
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateOutputFlags(cmd); err != nil {
+			return err
+		}
 		v := viper.New()
+		state := &configState{v: v}
 		if err := initViper(v, cmd); err != nil {
-			return err
+			// a broken profile selection must not lock out the config commands that repair it
+			if !isConfigCommand(cmd) || !errors.Is(err, errUnknownProfile) {
+				return err
+			}
+			state.profileErr = err
 		}
 		// retrieve and validate config
 		cfg, err := retrieveConfig(v)
 		if err != nil {
 			return err
 		}
-		cmd.SetContext(cfg.ToContext(cmd.Context()))
+		cmd.SetContext(state.toContext(cfg.ToContext(cmd.Context())))
 
-		if flagShowConfig {
-			cfgpath := v.ConfigFileUsed()
-			if cfgpath != "" {
-				cmd.Println("config loaded:", cfgpath)
-			}
-			cmd.Println(cfg.String())
-			os.Exit(0)
-		}
 		// Setup logger
 		log := logging.New(logging.LoggerConfig{
 			Level:      v.GetString("log.level"),
@@ -52,19 +81,30 @@ var rootCmd = &cobra.Command{
 			WithSource: v.GetBool("log.with_source"),
 		})
 		cmd.SetContext(logging.ToContext(cmd.Context(), log))
+		if cfg.Profile != "" {
+			log.Debug(fmt.Sprintf("using profile %q", cfg.Profile))
+		}
 
 		if cmd.Name() == "help" || cmd.Name() == "version" || cmd.Name() == "completion" {
 			return nil
 		}
+		// the config commands work on the config file itself and need no valid connection settings
+		if isConfigCommand(cmd) {
+			return nil
+		}
 		if cmd.Flags().Changed("help") {
 			return nil
 		}
 
+		// resolve file:, cmd: and env: secret references only now that a connection is needed
+		if err := cfg.ResolveSecrets(cmd.Context()); err != nil {
+			return fmt.Errorf("resolve secrets: %w", err)
+		}
 		if err := cfg.Validate(); err != nil {
 			return fmt.Errorf("validate config: %w", err)
 		}
 
-		httpSvc, err := httpc.New(cfg, log, httpc.WithCookieJar())
+		httpSvc, err := httpc.New(cfg, log, httpc.WithCookieJar(), httpc.WithDryRun(flagDryRun))
 		if err != nil {
 			return fmt.Errorf("http service: %w", err)
 		}
@@ -75,7 +115,7 @@ var rootCmd = &cobra.Command{
 		if err := authenticator.Init(cmd.Context()); err != nil {
 			return fmt.Errorf("auth init: %w", err)
 		}
-		httpSvc.InstallAuthEditor(authenticator.Editor())
+		httpSvc.InstallAuthenticator(authenticator)
 
 		ctx := httpSvc.ToContext(cmd.Context())
 		ctx = authcore.ToContext(ctx, authenticator)
@@ -91,10 +131,23 @@ var rootCmd = &cobra.Command{
 	SilenceErrors: false,
 }
 
+// exitError ends the program with a specific exit code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
+		var ee *exitError
+		if errors.As(err, &ee) {
+			os.Exit(ee.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -103,6 +156,7 @@ func init() {
 	pf := rootCmd.PersistentFlags()
 
 	pf.String("config", "", "path to config file")
+	pf.StringP("profile", "p", "", "named profile of the config file to use (overrides current_profile, env CAMUNDER_PROFILE)")
 
 	pf.String("log-level", "info", "log level (debug, info, warn, error)")
 	pf.String("log-format", "plain", "log format (json, plain, text)")
@@ -113,6 +167,7 @@ func init() {
 	pf.String("auth-token-url", "", "auth token URL")
 	pf.String("auth-client-id", "", "auth client ID")
 	pf.String("auth-client-secret", "", "auth client secret")
+	pf.Bool("auth-token-cache", false, "cache OAuth2 access tokens on disk and reuse them until they expire")
 	pf.StringToString("auth-scopes", nil, "auth scopes as key=value (repeatable or comma-separated)")
 
 	pf.String("http-timeout", "", "HTTP timeout (Go duration, e.g. 30s)")
@@ -122,33 +177,18 @@ func init() {
 	pf.String("operate-base-url", "", "Operate API base URL")
 	pf.String("tasklist-base-url", "", "Tasklist API base URL")
 
-	// TODO show-config flag should be in a "config" subcommand
-	pf.BoolVar(&flagShowConfig, "show-config", false, "print effective config (secrets redacted)")
-
-	// TODO add --dry-run flag to commands that perform actions
+	pf.String("record", "", "record all HTTP requests and responses, redacted, as files to this dir")
+	pf.String("replay", "", "serve all HTTP requests from the files recorded to this dir, without a server")
+	pf.BoolVar(&flagDryRun, "dry-run", false, "show what mutating commands would do; no mutating request is sent")
+	pf.StringVarP(&flagOutput, "output", "o", "", "output format: json, yaml, table, wide, csv, ndjson, template=<go template>, jsonpath=<expression>")
 }
 
 func initViper(v *viper.Viper, cmd *cobra.Command) error {
-	// Resolve precedence: flags > env > config file > defaults
+	// Resolve precedence: flags > env > profile > config file > defaults
 	fs := cmd.Flags()
-	_ = v.BindPFlag("config", fs.Lookup("config"))
-
-	_ = v.BindPFlag("log.level", fs.Lookup("log-level"))
-	_ = v.BindPFlag("log.format", fs.Lookup("log-format"))
-	_ = v.BindPFlag("log.with_source", fs.Lookup("log-with-source"))
-
-	_ = v.BindPFlag("app.tenant", fs.Lookup("tenant"))
-	_ = v.BindPFlag("auth.token_url", fs.Lookup("auth-token-url"))
-	_ = v.BindPFlag("auth.client_id", fs.Lookup("auth-client-id"))
-	_ = v.BindPFlag("auth.client_secret", fs.Lookup("auth-client-secret"))
-	_ = v.BindPFlag("http.timeout", fs.Lookup("http-timeout"))
-
-	_ = v.BindPFlag("apis.version", fs.Lookup("camunda-apis-version"))
-	_ = v.BindPFlag("apis.camunda_api.base_url", fs.Lookup("camunda-base-url"))
-	_ = v.BindPFlag("apis.operate_api.base_url", fs.Lookup("operate-base-url"))
-	_ = v.BindPFlag("apis.tasklist_api.base_url", fs.Lookup("tasklist-base-url"))
-
-	_ = v.BindPFlag("tmp.auth_scopes", fs.Lookup("auth-scopes"))
+	for key, flag := range flagBindings {
+		_ = v.BindPFlag(key, fs.Lookup(flag))
+	}
 
 	// Force hardcoded keys
 	v.Set("apis.camunda_api.key", config.CamundaApiKeyConst)
@@ -157,6 +197,15 @@ func initViper(v *viper.Viper, cmd *cobra.Command) error {
 
 	// Defaults
 	v.SetDefault("http.timeout", "30s")
+	v.SetDefault("http.retry.strategy", "exponential")
+	v.SetDefault("http.retry.initial_delay", "500ms")
+	v.SetDefault("http.retry.max_delay", "5s")
+	v.SetDefault("http.retry.max_retries", 3)
+	v.SetDefault("http.retry.multiplier", 2.0)
+	v.SetDefault("http.retry.timeout", "20s")
+	v.SetDefault("http.rate_limit", 20.0)
+	v.SetDefault("http.burst", 10)
+	v.SetDefault("http.max_concurrent", 8)
 
 	// Config file discovery
 	if cfgFile := v.GetString("config"); cfgFile != "" {
@@ -181,10 +230,14 @@ func initViper(v *viper.Viper, cmd *cobra.Command) error {
 		}
 	}
 
-	// ENV: CAMUNDER_AUTH_CLIENT_ID, etc.
+	// ENV: CAMUNDER_AUTH_OAUTH2_CLIENT_ID, etc.
 	v.SetEnvPrefix("CAMUNDER")
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
+	// AutomaticEnv only covers keys viper already knows, bind the others so that they can be set by env only
+	for _, key := range config.Keys() {
+		_ = v.BindEnv(key)
+	}
 
 	// Read config (ignore "not found")
 	if err := v.ReadInConfig(); err != nil {
@@ -193,6 +246,31 @@ func initViper(v *viper.Viper, cmd *cobra.Command) error {
 			return fmt.Errorf("read config: %w", err)
 		}
 	}
+	if v.GetString("config") == "" {
+		v.Set("config", v.ConfigFileUsed())
+	}
+	return applyProfile(v)
+}
+
+// applyProfile merges the selected profile over the top-level settings of the config file, so flags
+// and env vars still take precedence. The profile is selected by --profile, CAMUNDER_PROFILE or
+// current_profile, in this order.
+func applyProfile(v *viper.Viper) error {
+	name := v.GetString("profile")
+	if name == "" {
+		name = v.GetString(config.CurrentProfileKey)
+	}
+	if name == "" {
+		return nil
+	}
+	key := config.ProfilesKey + "." + strings.ToLower(name)
+	if !v.IsSet(key) {
+		return fmt.Errorf("%w %q in config file %q", errUnknownProfile, name, v.ConfigFileUsed())
+	}
+	if err := v.MergeConfigMap(v.GetStringMap(key)); err != nil {
+		return fmt.Errorf("apply profile %q: %w", name, err)
+	}
+	v.Set("profile", name)
 	return nil
 }
 
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/grafvonb/camunder/internal/logging"
+	"github.com/grafvonb/camunder/internal/services/common"
+	"github.com/grafvonb/camunder/internal/services/variable"
+	"github.com/spf13/cobra"
+)
+
+var supportedResourcesForSet = common.ResourceTypes{
+	"var": "variable",
+}
+
+var (
+	flagElementInstanceKey int64
+	flagVariablesJSON      string
+	flagVariablesFile      string
+	flagVariablesLocal     bool
+)
+
+// setCmd represents the set command
+var setCmd = &cobra.Command{
+	Use:     "set [resource name]",
+	Short:   "Set resources of a given type, e.g. variables of an element instance. " + supportedResourcesForSet.PrettyString(),
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"s", "update"},
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logging.FromContext(cmd.Context())
+		rn := strings.ToLower(args[0])
+		svcs, err := NewFromContext(cmd.Context())
+		if err != nil {
+			log.Error(fmt.Sprintf("%v", err))
+			return
+		}
+
+		switch rn {
+		case "variable", "var":
+			vars, err := readVariables(flagVariablesJSON, flagVariablesFile)
+			if err != nil {
+				log.Error(fmt.Sprintf("reading variables: %v", err))
+				return
+			}
+			if flagDryRun {
+				names := make([]string, 0, len(vars))
+				for n := range vars {
+					names = append(names, n)
+				}
+				slices.Sort(names)
+				scope := "propagated to the outermost scope defining them"
+				if flagVariablesLocal {
+					scope = "local"
+				}
+				dryRunView(cmd, "set variables on", "element instance(s)", []plannedAction{{
+					Key:    flagElementInstanceKey,
+					Action: fmt.Sprintf("set %s (%s)", strings.Join(names, ", "), scope),
+				}})
+				return
+			}
+			svc, err := variable.New(svcs.Config, svcs.HTTP.Client(), log)
+			if err != nil {
+				log.Error(fmt.Sprintf("creating variable service: %v", err))
+				return
+			}
+			if _, err = svc.SetVariables(cmd.Context(), flagElementInstanceKey, vars, flagVariablesLocal); err != nil {
+				log.Error(fmt.Sprintf("setting variables on element instance %d: %v", flagElementInstanceKey, err))
+				return
+			}
+			cmd.Println(fmt.Sprintf("%-16d ok: %d variable(s) set", flagElementInstanceKey, len(vars)))
+		default:
+			log.Error(fmt.Sprintf("unknown resource type: %s, supported: %s", rn, supportedResourcesForSet))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setCmd)
+
+	fs := setCmd.Flags()
+	fs.Int64Var(&flagElementInstanceKey, "element-instance-key", 0, "key of the element instance (scope) to set the variables on; a process instance key selects the process scope")
+	fs.StringVar(&flagVariablesJSON, "json", "", "variables as JSON object, e.g. '{\"approved\":true}'")
+	fs.StringVar(&flagVariablesFile, "file", "", "path to a file with the variables as JSON object")
+	fs.BoolVar(&flagVariablesLocal, "local", false, "set the variables in the given scope only instead of propagating them to upper scopes")
+
+	_ = setCmd.MarkFlagRequired("element-instance-key")
+	setCmd.MarkFlagsOneRequired("json", "file")
+	setCmd.MarkFlagsMutuallyExclusive("json", "file")
+}
+
+// readVariables decodes the variables object from the JSON string or, if empty, the file.
+// Numbers are kept as json.Number so that large integers are sent unchanged.
+func readVariables(raw, file string) (map[string]any, error) {
+	data := []byte(raw)
+	if file != "" {
+		var err error
+		if data, err = os.ReadFile(file); err != nil {
+			return nil, err
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var vars map[string]any
+	if err := dec.Decode(&vars); err != nil {
+		return nil, fmt.Errorf("variables must be a JSON object: %w", err)
+	}
+	if len(vars) == 0 {
+		return nil, errors.New("no variables given")
+	}
+	return vars, nil
+}
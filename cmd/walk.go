@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -16,16 +17,30 @@ var supportedResourcesForWalk = common.ResourceTypes{
 }
 
 var (
-	flagStartKey int64
-	flagWalkMode string
+	flagStartKey   int64
+	flagWalkMode   string
+	flagWalkFormat string
+	flagWalkASCII  bool
+
+	flagWalkMaxDepth int
+	flagWalkMaxNodes int
 )
 
+const defaultWalkMaxNodes = 10000
+
 var validWalkModes = map[string]bool{
 	"parent":   true,
 	"children": true,
 	"family":   true,
 }
 
+var validWalkFormats = map[string]bool{
+	"list":    true,
+	"tree":    true,
+	"dot":     true,
+	"mermaid": true,
+}
+
 var walkCmd = &cobra.Command{
 	Use:     "walk [resource type]",
 	Short:   "Traverse (walk) the parent/child graph of resource type. " + supportedResourcesForWalk.PrettyString(),
@@ -37,6 +52,14 @@ var walkCmd = &cobra.Command{
 			log.Error(fmt.Sprintf("invalid value for --walk: %q (must be parent, children, or family)", flagWalkMode))
 			return
 		}
+		if !validWalkFormats[flagWalkFormat] {
+			log.Error(fmt.Sprintf("invalid value for --format: %q (must be list, tree, dot, or mermaid)", flagWalkFormat))
+			return
+		}
+		if flagWalkFormat != "list" && (flagOutput != "" || flagKeysOnly) {
+			log.Error(fmt.Sprintf("--format %s cannot be combined with --output or --keys-only", flagWalkFormat))
+			return
+		}
 		rn := strings.ToLower(args[0])
 		svcs, err := NewFromContext(cmd.Context())
 		if err != nil {
@@ -56,31 +79,74 @@ var walkCmd = &cobra.Command{
 				return
 			}
 
+			log.Debug(fmt.Sprintf("walking ancestry by %s", walkerSvc.AncestryStrategy()))
+
+			// the list view is printed while walking, the other views need the whole tree
+			streaming := flagWalkFormat == "list" && flagOutput == "" && flagWalkMode != "parent"
+			walkOpts := []piapi.WalkOption{
+				piapi.WithWalkParallel(flagParallel),
+				piapi.WithMaxDepth(flagWalkMaxDepth),
+				piapi.WithMaxNodes(flagWalkMaxNodes),
+			}
+			if streaming {
+				walkOpts = append(walkOpts, piapi.WithOnFound(func(pi piapi.ProcessInstance, _ int) {
+					if flagKeysOnly {
+						cmd.Println(pi.Key)
+						return
+					}
+					cmd.Println(oneLineProcessInstance(pi))
+				}))
+			}
+
 			var path KeysPath
+			var edges Edges
 			var chain Chain
 			switch flagWalkMode {
 			case "parent":
 				_, path, chain, err = walkerSvc.Ancestry(cmd.Context(), flagStartKey)
-				if err != nil {
-					return
-				}
+				edges = EdgesOfPath(path)
 			case "children":
-				path, _, chain, err = walkerSvc.Descendants(cmd.Context(), flagStartKey)
-				if err != nil {
-					return
-				}
+				path, edges, chain, err = walkerSvc.Descendants(cmd.Context(), flagStartKey, walkOpts...)
 			case "family":
-				path, _, chain, err = walkerSvc.Family(cmd.Context(), flagStartKey)
-				if err != nil {
-					return
-				}
+				path, edges, chain, err = walkerSvc.Family(cmd.Context(), flagStartKey, walkOpts...)
 			default:
 				return
 			}
+			if errors.Is(err, piapi.ErrMaxNodesReached) {
+				log.Warn(fmt.Sprintf("walk stopped after %d process instances, use a higher --max-nodes to see all", len(path)))
+			} else if err != nil {
+				log.Error(fmt.Sprintf("error walking process instances from %d: %v", flagStartKey, err))
+				return
+			}
+			if streaming {
+				return
+			}
+			switch flagWalkFormat {
+			case "tree":
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), edges.Tree(chain, flagStartKey, flagWalkASCII))
+				return
+			case "dot":
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), edges.Dot(chain, flagStartKey))
+				return
+			case "mermaid":
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), edges.Mermaid(chain, flagStartKey))
+				return
+			}
+			if flagOutput != "" {
+				items := path.Items(chain)
+				if err = renderOutput(cmd, items, items); err != nil {
+					log.Error(fmt.Sprintf("error rendering walk: %v", err))
+				}
+				return
+			}
 			if flagKeysOnly {
 				cmd.Println(path.KeysOnly(chain))
 				return
 			}
+			if flagWalkMode == "parent" && walkerSvc.AncestryStrategy() == piapi.AncestryByCallHierarchy {
+				cmd.Println(path.HierarchyLine(chain))
+				return
+			}
 			cmd.Println(path.StandardLine(chain))
 		default:
 			log.Error(fmt.Sprintf("unknown resource type: %s, supported: %s", rn, supportedResourcesForWalk))
@@ -96,7 +162,12 @@ func init() {
 	_ = walkCmd.MarkFlagRequired("start-key")
 	fs.StringVarP(&flagWalkMode, "mode", "m", "", "walk mode: parent, children, family")
 	_ = walkCmd.MarkFlagRequired("mode")
+	fs.IntVar(&flagWalkMaxDepth, "max-depth", 0, "levels below the root to walk in children and family mode (0 = no limit)")
+	fs.IntVar(&flagWalkMaxNodes, "max-nodes", defaultWalkMaxNodes, "stop walking after this many process instances (0 = no limit)")
+	fs.IntVar(&flagParallel, "parallel", 0, fmt.Sprintf("number of children searches run in parallel (0 = %d)", piapi.DefaultWalkParallel))
 
 	// view options
 	fs.BoolVarP(&flagKeysOnly, "keys-only", "", false, "only print the keys of the resources")
+	fs.StringVarP(&flagWalkFormat, "format", "f", "list", "view: list, tree (indented with state and incident markers), dot (Graphviz) or mermaid")
+	fs.BoolVar(&flagWalkASCII, "ascii", false, "draw the tree with ASCII instead of Unicode box drawing characters")
 }
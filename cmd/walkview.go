@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
@@ -19,24 +20,27 @@ func (p KeysPath) KeysOnly(c Chain) string {
 }
 
 func (p KeysPath) StandardLine(c Chain) string {
-	return p.join(c, func(item processinstance.ProcessInstance) string {
-		var pTag, eTag, vTag string
-		if item.ProcessVersion > 0 {
-			pTag = fmt.Sprintf(" p:%d", item.ParentKey)
-		} else {
-			pTag = " p:<root>"
-		}
-		if item.EndDate != "" {
-			eTag = fmt.Sprintf(" e:%s", item.EndDate)
-		}
-		if item.ProcessVersionTag != "" {
-			vTag = "/" + item.ProcessVersionTag
-		}
+	return p.join(c, oneLineProcessInstance, "\n")
+}
 
-		return fmt.Sprintf(
-			"%-16d %s %s v%s%s %s s:%s%s%s i:%t",
-			item.Key, item.TenantId, item.BpmnProcessId, version, vTag, item.State, item.StartDate, eTag, pTag, item.Incident,
-		)
+// Items returns the process instances of the path in path order.
+func (p KeysPath) Items(c Chain) []processinstance.ProcessInstance {
+	items := make([]processinstance.ProcessInstance, 0, len(p))
+	for _, k := range p {
+		items = append(items, c[k])
+	}
+	return items
+}
+
+// HierarchyLine is the list view of an ancestry from the call hierarchy, whose instances have no
+// state, version or dates, see processinstance.AncestryByCallHierarchy.
+func (p KeysPath) HierarchyLine(c Chain) string {
+	return p.join(c, func(it processinstance.ProcessInstance) string {
+		pTag := " p:<root>"
+		if it.ParentKey > 0 {
+			pTag = fmt.Sprintf(" p:%d", it.ParentKey)
+		}
+		return fmt.Sprintf("%-16d %s d:%d%s", it.Key, processName(it), it.ProcessDefinitionKey, pTag)
 	}, "\n")
 }
 
@@ -61,3 +65,245 @@ func (p KeysPath) join(c Chain, label Label, sep string) string {
 	}
 	return strings.Join(out, sep)
 }
+
+// Edges maps process instance keys to the keys of their direct children.
+type Edges map[int64][]int64
+
+// EdgesOfPath returns the edges of an ancestry path, which runs from the start up to the root.
+func EdgesOfPath(p KeysPath) Edges {
+	e := make(Edges, len(p))
+	for i, k := range p {
+		if i+1 < len(p) {
+			e[p[i+1]] = append(e[p[i+1]], k)
+		}
+		if _, ok := e[k]; !ok {
+			e[k] = nil
+		}
+	}
+	return e
+}
+
+// roots returns the keys that are no child of another key, in key order.
+func (e Edges) roots() []int64 {
+	isChild := make(map[int64]bool)
+	for _, children := range e {
+		for _, k := range children {
+			isChild[k] = true
+		}
+	}
+	var roots []int64
+	for k := range e {
+		if !isChild[k] {
+			roots = append(roots, k)
+		}
+	}
+	slices.Sort(roots)
+	return roots
+}
+
+// children returns the direct children of a key in key order, so that the output is stable.
+func (e Edges) children(k int64) []int64 {
+	return slices.Sorted(slices.Values(e[k]))
+}
+
+// Tree renders the edges as indented tree, with Unicode box drawing or, if ascii is set, plain ASCII.
+// Each process instance is shown with its state; incidents and the start key are marked.
+func (e Edges) Tree(c Chain, start int64, ascii bool) string {
+	branch, last, pipe, incident := "├── ", "└── ", "│   ", "⚠ incident"
+	if ascii {
+		branch, last, pipe, incident = "|-- ", "`-- ", "|   ", "! incident"
+	}
+	label := func(k int64) string {
+		it, ok := c[k]
+		if !ok {
+			return fmt.Sprintf("%d <not found>", k)
+		}
+		name, version, state := nodeParts(it)
+		if state != "" {
+			state = "[" + state + "]"
+		}
+		s := joinNonEmpty(" ", fmt.Sprint(it.Key), name, version, state)
+		if it.Incident {
+			s += " " + incident
+		}
+		if k == start {
+			s += " (start)"
+		}
+		return s
+	}
+
+	var b strings.Builder
+	visited := make(map[int64]bool)
+	var walk func(k int64, prefix string)
+	walk = func(k int64, prefix string) {
+		children := e.children(k)
+		for i, child := range children {
+			connector, indent := branch, pipe
+			if i == len(children)-1 {
+				connector, indent = last, "    "
+			}
+			b.WriteString(prefix + connector + label(child) + "\n")
+			if !visited[child] {
+				visited[child] = true
+				walk(child, prefix+indent)
+			}
+		}
+	}
+	for _, root := range e.roots() {
+		visited[root] = true
+		b.WriteString(label(root) + "\n")
+		walk(root, "")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Dot renders the edges as Graphviz digraph, e.g. for dot -Tsvg. Nodes are colored by state,
+// incidents get a red border and the start key a bold one, a start with incident both.
+func (e Edges) Dot(c Chain, start int64) string {
+	var b strings.Builder
+	b.WriteString("digraph walk {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	for _, k := range e.keys() {
+		it := c[k]
+		name, version, state := nodeParts(it)
+		label := joinNonEmpty(`\n`, fmt.Sprint(k), dotEscape(joinNonEmpty(" ", name, version)), dotEscape(state))
+		attrs := fmt.Sprintf(`label="%s", fillcolor="%s"`, label, stateColor(it.State))
+		if it.Incident {
+			attrs += `, color="#d32f2f"`
+		}
+		switch {
+		case k == start:
+			attrs += ", penwidth=3"
+		case it.Incident:
+			attrs += ", penwidth=2"
+		}
+		fmt.Fprintf(&b, "  \"%d\" [%s];\n", k, attrs)
+	}
+	for _, k := range e.keys() {
+		for _, child := range e.children(k) {
+			fmt.Fprintf(&b, "  \"%d\" -> \"%d\";\n", k, child)
+		}
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// dotEscape escapes s for a quoted Graphviz string, in which only " and \ are special.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// Mermaid renders the edges as Mermaid flowchart, which renders in Markdown on GitHub, GitLab and Confluence.
+func (e Edges) Mermaid(c Chain, start int64) string {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	classes := make(map[string][]string)
+	for _, k := range e.keys() {
+		it := c[k]
+		id := fmt.Sprintf("pi%d", k)
+		name, version, state := nodeParts(it)
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", id, joinNonEmpty("<br/>", fmt.Sprint(k), joinNonEmpty(" ", mermaidEscape(name), version), state))
+		if class := stateClass(it.State); class != "" {
+			classes[class] = append(classes[class], id)
+		}
+		if it.Incident {
+			classes["incident"] = append(classes["incident"], id)
+		}
+		if k == start {
+			classes["start"] = append(classes["start"], id)
+		}
+	}
+	for _, k := range e.keys() {
+		for _, child := range e.children(k) {
+			fmt.Fprintf(&b, "  pi%d --> pi%d\n", k, child)
+		}
+	}
+	for _, class := range []string{"active", "completed", "canceled", "incident", "start"} {
+		if ids := classes[class]; len(ids) > 0 {
+			fmt.Fprintf(&b, "  classDef %s %s\n", class, mermaidClassStyles[class])
+			fmt.Fprintf(&b, "  class %s %s\n", strings.Join(ids, ","), class)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// keys returns all keys of the graph in tree order, parents before their children.
+func (e Edges) keys() []int64 {
+	var keys []int64
+	seen := make(map[int64]bool)
+	var walk func(int64)
+	walk = func(k int64) {
+		if seen[k] {
+			return
+		}
+		seen[k] = true
+		keys = append(keys, k)
+		for _, child := range e.children(k) {
+			walk(child)
+		}
+	}
+	for _, root := range e.roots() {
+		walk(root)
+	}
+	return keys
+}
+
+var mermaidClassStyles = map[string]string{
+	"active":    "fill:#bbdefb,stroke:#1976d2",
+	"completed": "fill:#c8e6c9,stroke:#388e3c",
+	"canceled":  "fill:#e0e0e0,stroke:#757575",
+	"incident":  "stroke:#d32f2f,stroke-width:3px",
+	"start":     "stroke-width:3px",
+}
+
+// processName names the process of an instance by its BPMN process id or, for the instances
+// of a call hierarchy, by the process definition name.
+func processName(it processinstance.ProcessInstance) string {
+	if it.BpmnProcessId != "" {
+		return it.BpmnProcessId
+	}
+	return it.ProcessDefinitionName
+}
+
+// nodeParts returns the name, version and state shown for a process instance in the tree and
+// graph views; the parts an instance lacks are empty.
+func nodeParts(it processinstance.ProcessInstance) (name, version, state string) {
+	if it.ProcessVersion > 0 {
+		version = fmt.Sprintf("v%d", it.ProcessVersion)
+	}
+	return processName(it), version, string(it.State)
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	return strings.Join(slices.DeleteFunc(parts, func(s string) bool { return s == "" }), sep)
+}
+
+// stateClass groups the states of the API versions, e.g. CANCELED (8.7) and TERMINATED (8.8).
+func stateClass(s processinstance.State) string {
+	switch strings.ToLower(string(s)) {
+	case "active":
+		return "active"
+	case "completed":
+		return "completed"
+	case "canceled", "terminated":
+		return "canceled"
+	}
+	return ""
+}
+
+func stateColor(s processinstance.State) string {
+	switch stateClass(s) {
+	case "active":
+		return "#bbdefb"
+	case "completed":
+		return "#c8e6c9"
+	case "canceled":
+		return "#e0e0e0"
+	}
+	return "#ffffff"
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
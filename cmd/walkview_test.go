@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/stretchr/testify/require"
+)
+
+var walkTestChain = Chain{
+	1: {Key: 1, BpmnProcessId: "order", ProcessVersion: 2, State: "ACTIVE"},
+	2: {Key: 2, BpmnProcessId: "payment", ProcessVersion: 1, State: "ACTIVE", ParentKey: 1, Incident: true},
+	3: {Key: 3, BpmnProcessId: "shipping", ProcessVersion: 4, State: "COMPLETED", ParentKey: 1},
+	4: {Key: 4, BpmnProcessId: "refund", ProcessVersion: 1, State: "TERMINATED", ParentKey: 2},
+}
+
+// the children are not in key order, as returned by a search
+var walkTestEdges = Edges{1: {3, 2}, 2: {4}, 3: nil, 4: nil}
+
+func TestEdges_Tree(t *testing.T) {
+	require.Equal(t, ""+
+		"1 order v2 [ACTIVE]\n"+
+		"├── 2 payment v1 [ACTIVE] ⚠ incident (start)\n"+
+		"│   └── 4 refund v1 [TERMINATED]\n"+
+		"└── 3 shipping v4 [COMPLETED]",
+		walkTestEdges.Tree(walkTestChain, 2, false))
+
+	require.Equal(t, ""+
+		"1 order v2 [ACTIVE]\n"+
+		"|-- 2 payment v1 [ACTIVE] ! incident (start)\n"+
+		"|   `-- 4 refund v1 [TERMINATED]\n"+
+		"`-- 3 shipping v4 [COMPLETED]",
+		walkTestEdges.Tree(walkTestChain, 2, true))
+}
+
+func TestEdges_Dot(t *testing.T) {
+	dot := walkTestEdges.Dot(walkTestChain, 1)
+	require.Contains(t, dot, `"1" [label="1\norder v2\nACTIVE", fillcolor="#bbdefb", penwidth=3];`)
+	require.Contains(t, dot, `"2" [label="2\npayment v1\nACTIVE", fillcolor="#bbdefb", color="#d32f2f", penwidth=2];`)
+	require.Contains(t, dot, `"4" [label="4\nrefund v1\nTERMINATED", fillcolor="#e0e0e0"];`)
+	require.Contains(t, dot, "  \"1\" -> \"2\";\n  \"1\" -> \"3\";\n  \"2\" -> \"4\";\n}")
+
+	// a start with incident keeps both marks
+	require.Contains(t, walkTestEdges.Dot(walkTestChain, 2), `"2" [label="2\npayment v1\nACTIVE", fillcolor="#bbdefb", color="#d32f2f", penwidth=3];`)
+}
+
+func TestEdges_DotEscapesOnlyQuoteAndBackslash(t *testing.T) {
+	chain := Chain{1: {Key: 1, ProcessDefinitionName: `Bestellung "Ä"\Eilig`}}
+	require.Contains(t, Edges{1: nil}.Dot(chain, 0), `"1" [label="1\nBestellung \"Ä\"\\Eilig", fillcolor="#ffffff"];`)
+}
+
+func TestEdges_Mermaid(t *testing.T) {
+	mm := walkTestEdges.Mermaid(walkTestChain, 4)
+	require.Contains(t, mm, "flowchart TD\n  pi1[\"1<br/>order v2<br/>ACTIVE\"]\n  pi2[")
+	require.Contains(t, mm, "  pi1 --> pi2\n  pi1 --> pi3\n  pi2 --> pi4\n")
+	require.Contains(t, mm, "  class pi1,pi2 active\n")
+	require.Contains(t, mm, "  class pi2 incident\n")
+	require.Contains(t, mm, "  class pi4 canceled\n")
+	require.Contains(t, mm, "  class pi4 start")
+}
+
+func TestEdgesOfPath(t *testing.T) {
+	// an ancestry path runs from the start up to the root
+	edges := EdgesOfPath(KeysPath{4, 2, 1})
+	require.Equal(t, Edges{1: {2}, 2: {4}, 4: nil}, edges)
+	require.Equal(t, "1 order v2 [ACTIVE]\n`-- 2 payment v1 [ACTIVE] ! incident\n    `-- 4 refund v1 [TERMINATED] (start)",
+		edges.Tree(walkTestChain, 4, true))
+}
+
+func TestEdges_TreeMissingInstance(t *testing.T) {
+	edges := Edges{1: {5}, 5: nil}
+	chain := Chain{1: processinstance.ProcessInstance{Key: 1, BpmnProcessId: "order", ProcessVersion: 1, State: "ACTIVE"}}
+	require.Equal(t, "1 order v1 [ACTIVE]\n`-- 5 <not found>", edges.Tree(chain, 0, true))
+}
+
+func TestEdges_CallHierarchyInstances(t *testing.T) {
+	// instances from the call hierarchy have no version and no state
+	chain := Chain{
+		1: {Key: 1, ProcessDefinitionKey: 9, ProcessDefinitionName: "Order"},
+		2: {Key: 2, ProcessDefinitionKey: 9, ProcessDefinitionName: "Order", ParentKey: 1},
+	}
+	path := KeysPath{2, 1}
+	edges := EdgesOfPath(path)
+	require.Equal(t, "1 Order\n`-- 2 Order (start)", edges.Tree(chain, 2, true))
+	require.Contains(t, edges.Dot(chain, 2), `"1" [label="1\nOrder", fillcolor="#ffffff"];`)
+	require.Contains(t, edges.Mermaid(chain, 2), "  pi1[\"1<br/>Order\"]\n")
+	require.Equal(t, "2                Order d:9 p:1\n1                Order d:9 p:<root>", path.HierarchyLine(chain))
+}
@@ -1,11 +1,29 @@
 package v87
 
 import (
+	"encoding/json"
+
 	"github.com/grafvonb/camunder/internal/api/convert"
 	"github.com/grafvonb/camunder/pkg/camunda/cluster"
+	"github.com/grafvonb/camunder/pkg/camunda/incident"
 	processinstance "github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/grafvonb/camunder/pkg/camunda/variable"
 )
 
+func (src MigrateProcessInstanceResponse) ToStable() processinstance.MigrateResponse {
+	return processinstance.MigrateResponse{
+		StatusCode: src.StatusCode(),
+		Status:     src.Status(),
+	}
+}
+
+func (src ModifyProcessInstanceResponse) ToStable() processinstance.ModifyResponse {
+	return processinstance.ModifyResponse{
+		StatusCode: src.StatusCode(),
+		Status:     src.Status(),
+	}
+}
+
 func (src CancelProcessInstanceResponse) ToStable() processinstance.CancelResponse {
 	return processinstance.CancelResponse{
 		StatusCode: src.StatusCode(),
@@ -13,6 +31,41 @@ func (src CancelProcessInstanceResponse) ToStable() processinstance.CancelRespon
 	}
 }
 
+func (src ResolveIncidentResponse) ToStable() incident.ResolveResponse {
+	return incident.ResolveResponse{
+		StatusCode: src.StatusCode(),
+		Status:     src.Status(),
+	}
+}
+
+func (src UpdateElementInstanceVariablesResponse) ToStable() variable.SetResponse {
+	return variable.SetResponse{
+		StatusCode: src.StatusCode(),
+		Status:     src.Status(),
+	}
+}
+
+func (src CreateProcessInstanceCreated) ToStable() processinstance.CreateResponse {
+	return processinstance.CreateResponse{
+		ProcessInstance: processinstance.ProcessInstance{
+			BpmnProcessId:        src.ProcessDefinitionId,
+			Key:                  parseNumber(src.ProcessInstanceKey),
+			ProcessDefinitionKey: parseNumber(src.ProcessDefinitionKey),
+			ProcessVersion:       src.ProcessDefinitionVersion,
+			TenantId:             src.TenantId,
+		},
+		Variables: src.Variables,
+	}
+}
+
+func parseNumber(n json.Number) int64 {
+	v, err := n.Int64()
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
 func (src TopologyResponse) ToStable() (cluster.Topology, error) {
 	br, err := convert.MapNullableSlice(src.Brokers, func(b BrokerInfo) cluster.Broker { return b.ToStable() })
 	if err != nil {
@@ -0,0 +1,15 @@
+package v87
+
+import "encoding/json"
+
+// CreateProcessInstanceCreated is the response body of CreateProcessInstance. The generated
+// CreateProcessInstanceResult lacks the keys, which the spec adds with allOf. The keys are numbers
+// or strings depending on the negotiated content type, json.Number accepts both.
+type CreateProcessInstanceCreated struct {
+	ProcessDefinitionId      string         `json:"processDefinitionId"`
+	ProcessDefinitionKey     json.Number    `json:"processDefinitionKey"`
+	ProcessDefinitionVersion int32          `json:"processDefinitionVersion"`
+	ProcessInstanceKey       json.Number    `json:"processInstanceKey"`
+	TenantId                 string         `json:"tenantId"`
+	Variables                map[string]any `json:"variables,omitempty"`
+}
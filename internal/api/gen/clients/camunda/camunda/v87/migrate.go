@@ -0,0 +1,10 @@
+package v87
+
+// ProcessInstanceMigration is the request body of MigrateProcessInstance. The generated
+// ProcessInstanceMigrationInstruction lacks targetProcessDefinitionKey, which the spec adds with allOf;
+// send it with MigrateProcessInstanceWithBodyWithResponse.
+type ProcessInstanceMigration struct {
+	TargetProcessDefinitionKey string                                     `json:"targetProcessDefinitionKey"`
+	MappingInstructions        []MigrateProcessInstanceMappingInstruction `json:"mappingInstructions"`
+	OperationReference         *int64                                     `json:"operationReference,omitempty"`
+}
@@ -0,0 +1,18 @@
+package v87
+
+// ProcessInstanceModification is the request body of ModifyProcessInstance. The generated
+// ProcessInstanceModificationInstruction only has operationReference, the activate and terminate
+// instructions are added with allOf; send it with ModifyProcessInstanceWithBodyWithResponse.
+type ProcessInstanceModification struct {
+	ActivateInstructions  []ProcessInstanceModificationActivation           `json:"activateInstructions,omitempty"`
+	TerminateInstructions []ProcessInstanceModificationTerminateInstruction `json:"terminateInstructions,omitempty"`
+	OperationReference    *int64                                            `json:"operationReference,omitempty"`
+}
+
+// ProcessInstanceModificationActivation restores ancestorElementInstanceKey, which the generated
+// ProcessInstanceModificationActivateInstruction lacks for the same reason.
+type ProcessInstanceModificationActivation struct {
+	ElementId                  string                                      `json:"elementId"`
+	AncestorElementInstanceKey *string                                     `json:"ancestorElementInstanceKey,omitempty"`
+	VariableInstructions       *[]ModifyProcessInstanceVariableInstruction `json:"variableInstructions,omitempty"`
+}
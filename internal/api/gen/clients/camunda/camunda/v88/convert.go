@@ -1,10 +1,21 @@
 package v88
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/grafvonb/camunder/internal/api/convert"
 	"github.com/grafvonb/camunder/pkg/camunda/cluster"
+	"github.com/grafvonb/camunder/pkg/camunda/incident"
+	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/grafvonb/camunder/pkg/camunda/variable"
 )
 
+// stateTerminated is the 8.8 name of the state called CANCELED in Operate and in the stable API.
+const stateTerminated = "TERMINATED"
+
 func (src TopologyResponse) ToStable() (cluster.Topology, error) {
 	return cluster.Topology{
 		Brokers:               convert.MapSlice(src.Brokers, func(b BrokerInfo) cluster.Broker { return b.ToStable() }),
@@ -33,3 +44,217 @@ func (src Partition) ToStable() cluster.Partition {
 		Role:        cluster.PartitionRole(src.Role),
 	}
 }
+
+func (src MigrateProcessInstanceResponse) ToStable() processinstance.MigrateResponse {
+	return processinstance.MigrateResponse{
+		StatusCode: src.StatusCode(),
+		Status:     src.Status(),
+	}
+}
+
+func (src ModifyProcessInstanceResponse) ToStable() processinstance.ModifyResponse {
+	return processinstance.ModifyResponse{
+		StatusCode: src.StatusCode(),
+		Status:     src.Status(),
+	}
+}
+
+func (src CancelProcessInstanceResponse) ToStable() processinstance.CancelResponse {
+	return processinstance.CancelResponse{
+		StatusCode: src.StatusCode(),
+		Status:     src.Status(),
+	}
+}
+
+func (src CreateProcessInstanceResult) ToStable() processinstance.CreateResponse {
+	return processinstance.CreateResponse{
+		ProcessInstance: processinstance.ProcessInstance{
+			BpmnProcessId:        src.ProcessDefinitionId,
+			Key:                  ParseKey(src.ProcessInstanceKey),
+			ProcessDefinitionKey: ParseKey(src.ProcessDefinitionKey),
+			ProcessVersion:       src.ProcessDefinitionVersion,
+			TenantId:             src.TenantId,
+		},
+		Variables: src.Variables,
+	}
+}
+
+func (src ProcessInstanceResult) ToStable() processinstance.ProcessInstance {
+	parentKey := convert.DerefMap(src.ParentProcessInstanceKey, ParseKey, 0)
+	return processinstance.ProcessInstance{
+		BpmnProcessId:             src.ProcessDefinitionId,
+		Key:                       ParseKey(src.ProcessInstanceKey),
+		EndDate:                   convert.DerefMap(src.EndDate, formatDate, ""),
+		Incident:                  src.HasIncident,
+		ParentFlowNodeInstanceKey: convert.DerefMap(src.ParentElementInstanceKey, ParseKey, 0),
+		ParentKey:                 parentKey,
+		ParentProcessInstanceKey:  parentKey,
+		ProcessDefinitionKey:      ParseKey(src.ProcessDefinitionKey),
+		ProcessDefinitionName:     src.ProcessDefinitionName,
+		ProcessVersion:            src.ProcessDefinitionVersion,
+		ProcessVersionTag:         convert.Deref(src.ProcessDefinitionVersionTag, ""),
+		StartDate:                 formatDate(src.StartDate),
+		State:                     StateToStable(src.State),
+		TenantId:                  src.TenantId,
+	}
+}
+
+// ToStable returns the instance with the few fields a call hierarchy entry has.
+func (src ProcessInstanceCallHierarchyEntry) ToStable() processinstance.ProcessInstance {
+	return processinstance.ProcessInstance{
+		Key:                   ParseKey(src.ProcessInstanceKey),
+		ProcessDefinitionKey:  ParseKey(src.ProcessDefinitionKey),
+		ProcessDefinitionName: src.ProcessDefinitionName,
+	}
+}
+
+func (src *ProcessInstanceQueryResult) ToStable() processinstance.ProcessInstances {
+	var out processinstance.ProcessInstances
+	if src == nil {
+		return out
+	}
+	out.Total = int32(src.Page.TotalItems)
+	out.Items = convert.MapSlice(src.Items, func(i ProcessInstanceResult) processinstance.ProcessInstance {
+		return i.ToStable()
+	})
+	return out
+}
+
+func (src ResolveIncidentResponse) ToStable() incident.ResolveResponse {
+	return incident.ResolveResponse{
+		StatusCode: src.StatusCode(),
+		Status:     src.Status(),
+	}
+}
+
+func (src IncidentResult) ToStable() incident.Incident {
+	return incident.Incident{
+		Key:                  convert.DerefMap(src.IncidentKey, ParseKey, 0),
+		BpmnProcessId:        convert.Deref(src.ProcessDefinitionId, ""),
+		CreationTime:         convert.DerefMap(src.CreationTime, formatDate, ""),
+		ErrorType:            convert.DerefMap(src.ErrorType, func(t IncidentResultErrorType) string { return string(t) }, ""),
+		ErrorMessage:         convert.Deref(src.ErrorMessage, ""),
+		JobKey:               convert.DerefMap(src.JobKey, ParseKey, 0),
+		ProcessDefinitionKey: convert.DerefMap(src.ProcessDefinitionKey, ParseKey, 0),
+		ProcessInstanceKey:   convert.DerefMap(src.ProcessInstanceKey, ParseKey, 0),
+		State:                convert.DerefMap(src.State, func(s IncidentResultState) incident.State { return incident.State(s) }, ""),
+		TenantId:             convert.Deref(src.TenantId, ""),
+	}
+}
+
+func (src *IncidentQueryResult) ToStable() incident.Incidents {
+	var out incident.Incidents
+	if src == nil {
+		return out
+	}
+	out.Total = int32(src.Page.TotalItems)
+	out.Items = convert.MapSlice(src.Items, func(i IncidentResult) incident.Incident {
+		return i.ToStable()
+	})
+	return out
+}
+
+func (src UpdateElementInstanceVariablesResponse) ToStable() variable.SetResponse {
+	return variable.SetResponse{
+		StatusCode: src.StatusCode(),
+		Status:     src.Status(),
+	}
+}
+
+func (src VariableValueResult) ToStable() variable.Variable {
+	return variable.Variable{
+		Key:                ParseKey(src.VariableKey),
+		Name:               src.Name,
+		Value:              variable.DecodeValue(src.Value),
+		Truncated:          src.IsTruncated,
+		ProcessInstanceKey: ParseKey(src.ProcessInstanceKey),
+		ScopeKey:           ParseKey(src.ScopeKey),
+		TenantId:           src.TenantId,
+	}
+}
+
+func (src *VariableQueryResult) ToStable() variable.Variables {
+	var out variable.Variables
+	if src == nil {
+		return out
+	}
+	out.Total = int32(src.Page.TotalItems)
+	out.Items = convert.MapSlice(src.Items, func(i VariableValueResult) variable.Variable {
+		return i.ToStable()
+	})
+	return out
+}
+
+func (src ElementInstanceResult) ToStable() processinstance.ElementInstance {
+	return processinstance.ElementInstance{
+		Key:         ParseKey(src.ElementInstanceKey),
+		ElementId:   src.ElementId,
+		Name:        src.ElementName,
+		Type:        string(src.Type),
+		State:       strings.ToUpper(fmt.Sprint(src.State)),
+		StartDate:   formatDate(src.StartDate),
+		EndDate:     convert.DerefMap(src.EndDate, formatDate, ""),
+		Incident:    src.HasIncident,
+		IncidentKey: convert.DerefMap(src.IncidentKey, ParseKey, 0),
+	}
+}
+
+func (src ProcessElementStatisticsResult) ToStable() processinstance.ElementStatistics {
+	return processinstance.ElementStatistics{
+		ElementId: convert.Deref(src.ElementId, ""),
+		Active:    int64(convert.Deref(src.Active, 0)),
+		Canceled:  int64(convert.Deref(src.Canceled, 0)),
+		Completed: int64(convert.Deref(src.Completed, 0)),
+		Incidents: int64(convert.Deref(src.Incidents, 0)),
+	}
+}
+
+// StateToStable maps an 8.8 process instance state onto the stable state (TERMINATED -> CANCELED).
+func StateToStable(v ProcessInstanceStateEnum) processinstance.State {
+	if v == nil {
+		return ""
+	}
+	s := strings.ToUpper(fmt.Sprint(v))
+	if s == stateTerminated {
+		return processinstance.State(strings.ToUpper(processinstance.StateCanceled.String()))
+	}
+	return processinstance.State(s)
+}
+
+// StateFromStable maps a stable state onto the 8.8 process instance state; returns nil for StateAll.
+func StateFromStable(s processinstance.State) *string {
+	if s == "" || s == processinstance.StateAll {
+		return nil
+	}
+	if s.EqualsIgnoreCase(processinstance.StateCanceled) {
+		return convert.Ptr(stateTerminated)
+	}
+	return convert.Ptr(strings.ToUpper(s.String()))
+}
+
+// ParseKey converts an 8.8 resource key (a Java long serialized as string) to int64; returns 0 if malformed.
+func ParseKey(k LongKey) int64 {
+	v, err := strconv.ParseInt(k, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// FormatKey converts an int64 key to the string representation used by the 8.8 API.
+func FormatKey(k int64) LongKey {
+	return strconv.FormatInt(k, 10)
+}
+
+func formatDate(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func (src BatchOperationCreatedResult) ToStable() processinstance.BatchOperation {
+	ret := processinstance.BatchOperation{Type: string(convert.Deref(src.BatchOperationType, ""))}
+	if src.BatchOperationKey != nil {
+		// the key is either a long key or a UUID, both are sent as JSON strings
+		ret.Key, _ = src.BatchOperationKey.AsBatchOperationKey0()
+	}
+	return ret
+}
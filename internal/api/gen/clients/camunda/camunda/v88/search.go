@@ -0,0 +1,119 @@
+package v88
+
+// The generated ProcessInstanceSearchQuery, IncidentSearchQuery, VariableSearchQuery and ElementInstanceSearchQuery
+// (and their results) are aliases of SearchQueryRequest/SearchQueryResponse, because oapi-codegen does not
+// merge the allOf used in the 8.8 spec. They lose filter, sort and items. The types below restore the parts camunder needs;
+// send them with the *WithBodyWithResponse client methods and decode the raw response body.
+
+// ProcessInstanceQueryFilter is the subset of the 8.8 process instance filter used by camunder.
+// Only exact-match values are supported, the advanced filter operators are not exposed.
+type ProcessInstanceQueryFilter struct {
+	ProcessDefinitionId         *string               `json:"processDefinitionId,omitempty"`
+	ProcessDefinitionVersion    *int32                `json:"processDefinitionVersion,omitempty"`
+	ProcessDefinitionVersionTag *string               `json:"processDefinitionVersionTag,omitempty"`
+	ProcessDefinitionKey        *ProcessDefinitionKey `json:"processDefinitionKey,omitempty"`
+	ProcessInstanceKey          *ProcessInstanceKey   `json:"processInstanceKey,omitempty"`
+	ParentProcessInstanceKey    *ProcessInstanceKey   `json:"parentProcessInstanceKey,omitempty"`
+	State                       *string               `json:"state,omitempty"`
+	HasIncident                 *bool                 `json:"hasIncident,omitempty"`
+	TenantId                    *TenantId             `json:"tenantId,omitempty"`
+}
+
+// ProcessInstanceQuery is the request body of SearchProcessInstances.
+type ProcessInstanceQuery struct {
+	Filter *ProcessInstanceQueryFilter              `json:"filter,omitempty"`
+	Sort   *[]ProcessInstanceSearchQuerySortRequest `json:"sort,omitempty"`
+	Page   *SearchQueryPageRequest                  `json:"page,omitempty"`
+}
+
+// ProcessInstanceQueryResult is the response body of SearchProcessInstances.
+type ProcessInstanceQueryResult struct {
+	Items []ProcessInstanceResult `json:"items"`
+	Page  SearchQueryPageResponse `json:"page"`
+}
+
+// IncidentQueryFilter is the subset of the 8.8 incident filter used by camunder.
+type IncidentQueryFilter struct {
+	IncidentKey         *IncidentKey         `json:"incidentKey,omitempty"`
+	ProcessDefinitionId *ProcessDefinitionId `json:"processDefinitionId,omitempty"`
+	ProcessInstanceKey  *ProcessInstanceKey  `json:"processInstanceKey,omitempty"`
+	JobKey              *JobKey              `json:"jobKey,omitempty"`
+	ErrorType           *string              `json:"errorType,omitempty"`
+	State               *string              `json:"state,omitempty"`
+	TenantId            *TenantId            `json:"tenantId,omitempty"`
+}
+
+// IncidentQuery is the request body of SearchIncidents.
+type IncidentQuery struct {
+	Filter *IncidentQueryFilter              `json:"filter,omitempty"`
+	Sort   *[]IncidentSearchQuerySortRequest `json:"sort,omitempty"`
+	Page   *SearchQueryPageRequest           `json:"page,omitempty"`
+}
+
+// IncidentQueryResult is the response body of SearchIncidents.
+type IncidentQueryResult struct {
+	Items []IncidentResult        `json:"items"`
+	Page  SearchQueryPageResponse `json:"page"`
+}
+
+// VariableQueryFilter is the subset of the 8.8 variable filter used by camunder.
+type VariableQueryFilter struct {
+	Name               *string             `json:"name,omitempty"`
+	ProcessInstanceKey *ProcessInstanceKey `json:"processInstanceKey,omitempty"`
+	ScopeKey           *ScopeKey           `json:"scopeKey,omitempty"`
+	TenantId           *TenantId           `json:"tenantId,omitempty"`
+}
+
+// VariableQuery is the request body of SearchVariables.
+type VariableQuery struct {
+	Filter *VariableQueryFilter              `json:"filter,omitempty"`
+	Sort   *[]VariableSearchQuerySortRequest `json:"sort,omitempty"`
+	Page   *SearchQueryPageRequest           `json:"page,omitempty"`
+}
+
+// VariableValueResult is a variable as returned by GetVariable and SearchVariables. The generated
+// VariableResult lacks value and isTruncated, which the spec adds with allOf as well.
+type VariableValueResult struct {
+	Name               string             `json:"name"`
+	Value              string             `json:"value"`
+	IsTruncated        bool               `json:"isTruncated"`
+	ProcessInstanceKey ProcessInstanceKey `json:"processInstanceKey"`
+	ScopeKey           ScopeKey           `json:"scopeKey"`
+	TenantId           TenantId           `json:"tenantId"`
+	VariableKey        VariableKey        `json:"variableKey"`
+}
+
+// VariableQueryResult is the response body of SearchVariables.
+type VariableQueryResult struct {
+	Items []VariableValueResult   `json:"items"`
+	Page  SearchQueryPageResponse `json:"page"`
+}
+
+// ElementInstanceQueryFilter is the subset of the 8.8 element instance filter used by camunder.
+type ElementInstanceQueryFilter struct {
+	ProcessInstanceKey *ProcessInstanceKey `json:"processInstanceKey,omitempty"`
+	ElementId          *ElementId          `json:"elementId,omitempty"`
+	State              *string             `json:"state,omitempty"`
+	TenantId           *TenantId           `json:"tenantId,omitempty"`
+}
+
+// ElementInstanceQuery is the request body of SearchElementInstances.
+type ElementInstanceQuery struct {
+	Filter *ElementInstanceQueryFilter              `json:"filter,omitempty"`
+	Sort   *[]ElementInstanceSearchQuerySortRequest `json:"sort,omitempty"`
+	Page   *SearchQueryPageRequest                  `json:"page,omitempty"`
+}
+
+// ElementInstanceQueryResult is the response body of SearchElementInstances.
+type ElementInstanceQueryResult struct {
+	Items []ElementInstanceResult `json:"items"`
+	Page  SearchQueryPageResponse `json:"page"`
+}
+
+// ProcessInstanceModificationBatch is the request body of CreateABatchOperationToModifyProcessInstances.
+// The generated ProcessInstanceFilter uses the advanced filter unions, ProcessInstanceQueryFilter sends the same
+// exact-match fields as the search.
+type ProcessInstanceModificationBatch struct {
+	Filter           ProcessInstanceQueryFilter                                 `json:"filter"`
+	MoveInstructions []ProcessInstanceModificationMoveBatchOperationInstruction `json:"moveInstructions"`
+}
@@ -2,8 +2,10 @@ package v87
 
 import (
 	"github.com/grafvonb/camunder/internal/api/convert"
+	"github.com/grafvonb/camunder/pkg/camunda/incident"
 	"github.com/grafvonb/camunder/pkg/camunda/processdefinition"
 	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/grafvonb/camunder/pkg/camunda/variable"
 )
 
 func (src ProcessInstance) ToStable() processinstance.ProcessInstance {
@@ -79,3 +81,81 @@ func (src *ChangeStatus) ToStable() processinstance.ChangeStatus {
 		Message: convert.Deref(src.Message, ""),
 	}
 }
+
+func (src Incident) ToStable() incident.Incident {
+	return incident.Incident{
+		Key:                  convert.Deref(src.Key, 0),
+		CreationTime:         convert.Deref(src.CreationTime, ""),
+		ErrorType:            convert.DerefMap(src.Type, func(t IncidentType) string { return string(t) }, ""),
+		ErrorMessage:         convert.Deref(src.Message, ""),
+		JobKey:               convert.Deref(src.JobKey, 0),
+		ProcessDefinitionKey: convert.Deref(src.ProcessDefinitionKey, 0),
+		ProcessInstanceKey:   convert.Deref(src.ProcessInstanceKey, 0),
+		State:                convert.DerefMap(src.State, func(s IncidentState) incident.State { return incident.State(s) }, ""),
+		TenantId:             convert.Deref(src.TenantId, ""),
+	}
+}
+
+func (src *ResultsIncident) ToStable() incident.Incidents {
+	var out incident.Incidents
+	if src == nil {
+		return out
+	}
+	out.Total = int32(convert.Deref(src.Total, 0))
+	if src.Items != nil {
+		out.Items = convert.MapSlice(*src.Items, func(i Incident) incident.Incident {
+			return i.ToStable()
+		})
+	}
+	return out
+}
+
+func (src Variable) ToStable() variable.Variable {
+	return variable.Variable{
+		Key:                convert.Deref(src.Key, 0),
+		Name:               convert.Deref(src.Name, ""),
+		Value:              convert.DerefMap(src.Value, variable.DecodeValue, nil),
+		Truncated:          convert.Deref(src.Truncated, false),
+		ProcessInstanceKey: convert.Deref(src.ProcessInstanceKey, 0),
+		ScopeKey:           convert.Deref(src.ScopeKey, 0),
+		TenantId:           convert.Deref(src.TenantId, ""),
+	}
+}
+
+func (src *ResultsVariable) ToStable() variable.Variables {
+	var out variable.Variables
+	if src == nil {
+		return out
+	}
+	out.Total = int32(convert.Deref(src.Total, 0))
+	if src.Items != nil {
+		out.Items = convert.MapSlice(*src.Items, func(i Variable) variable.Variable {
+			return i.ToStable()
+		})
+	}
+	return out
+}
+
+func (src FlowNodeInstance) ToStable() processinstance.ElementInstance {
+	return processinstance.ElementInstance{
+		Key:         convert.Deref(src.Key, 0),
+		ElementId:   convert.Deref(src.FlowNodeId, ""),
+		Name:        convert.Deref(src.FlowNodeName, ""),
+		Type:        convert.DerefMap(src.Type, func(t FlowNodeInstanceType) string { return string(t) }, ""),
+		State:       convert.DerefMap(src.State, func(s FlowNodeInstanceState) string { return string(s) }, ""),
+		StartDate:   convert.Deref(src.StartDate, ""),
+		EndDate:     convert.Deref(src.EndDate, ""),
+		Incident:    convert.Deref(src.Incident, false),
+		IncidentKey: convert.Deref(src.IncidentKey, 0),
+	}
+}
+
+func (src FlowNodeStatistics) ToStable() processinstance.ElementStatistics {
+	return processinstance.ElementStatistics{
+		ElementId: convert.Deref(src.ActivityId, ""),
+		Active:    convert.Deref(src.Active, 0),
+		Canceled:  convert.Deref(src.Canceled, 0),
+		Completed: convert.Deref(src.Completed, 0),
+		Incidents: convert.Deref(src.Incidents, 0),
+	}
+}
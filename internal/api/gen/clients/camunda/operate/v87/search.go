@@ -0,0 +1,87 @@
+package v87
+
+import "encoding/json"
+
+// The generated QueryProcessInstance, QueryProcessDefinition, QueryIncident, QueryVariable and QueryFlowNodeInstance type searchAfter as a list of objects,
+// but Operate expects the scalar sortValues of the last item of the previous page. The query types below
+// carry the raw JSON array instead; send them with the *WithBodyWithResponse client methods.
+
+// QueryProcessInstanceAfter is QueryProcessInstance with a raw searchAfter cursor.
+type QueryProcessInstanceAfter struct {
+	Filter      *ProcessInstance `json:"filter,omitempty"`
+	SearchAfter json.RawMessage  `json:"searchAfter,omitempty"`
+	Size        *int32           `json:"size,omitempty"`
+	Sort        *[]Sort          `json:"sort,omitempty"`
+}
+
+// QueryProcessDefinitionAfter is QueryProcessDefinition with a raw searchAfter cursor.
+type QueryProcessDefinitionAfter struct {
+	Filter      *ProcessDefinition `json:"filter,omitempty"`
+	SearchAfter json.RawMessage    `json:"searchAfter,omitempty"`
+	Size        *int32             `json:"size,omitempty"`
+	Sort        *[]Sort            `json:"sort,omitempty"`
+}
+
+// QueryIncidentAfter is QueryIncident with a raw searchAfter cursor.
+type QueryIncidentAfter struct {
+	Filter      *Incident       `json:"filter,omitempty"`
+	SearchAfter json.RawMessage `json:"searchAfter,omitempty"`
+	Size        *int32          `json:"size,omitempty"`
+	Sort        *[]Sort         `json:"sort,omitempty"`
+}
+
+// QueryVariableAfter is QueryVariable with a raw searchAfter cursor.
+type QueryVariableAfter struct {
+	Filter      *Variable       `json:"filter,omitempty"`
+	SearchAfter json.RawMessage `json:"searchAfter,omitempty"`
+	Size        *int32          `json:"size,omitempty"`
+	Sort        *[]Sort         `json:"sort,omitempty"`
+}
+
+// QueryFlowNodeInstanceAfter is QueryFlowNodeInstance with a raw searchAfter cursor.
+type QueryFlowNodeInstanceAfter struct {
+	Filter      *FlowNodeInstance `json:"filter,omitempty"`
+	SearchAfter json.RawMessage   `json:"searchAfter,omitempty"`
+	Size        *int32            `json:"size,omitempty"`
+	Sort        *[]Sort           `json:"sort,omitempty"`
+}
+
+// Cursor returns the sortValues of the last item as raw JSON array, or nil if there are none.
+func (src *ResultsProcessInstance) Cursor() (json.RawMessage, error) {
+	if src == nil || src.SortValues == nil || len(*src.SortValues) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(*src.SortValues)
+}
+
+// Cursor returns the sortValues of the last item as raw JSON array, or nil if there are none.
+func (src *ResultsProcessDefinition) Cursor() (json.RawMessage, error) {
+	if src == nil || src.SortValues == nil || len(*src.SortValues) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(*src.SortValues)
+}
+
+// Cursor returns the sortValues of the last item as raw JSON array, or nil if there are none.
+func (src *ResultsIncident) Cursor() (json.RawMessage, error) {
+	if src == nil || src.SortValues == nil || len(*src.SortValues) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(*src.SortValues)
+}
+
+// Cursor returns the sortValues of the last item as raw JSON array, or nil if there are none.
+func (src *ResultsVariable) Cursor() (json.RawMessage, error) {
+	if src == nil || src.SortValues == nil || len(*src.SortValues) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(*src.SortValues)
+}
+
+// Cursor returns the sortValues of the last item as raw JSON array, or nil if there are none.
+func (src *ResultsFlowNodeInstance) Cursor() (json.RawMessage, error) {
+	if src == nil || src.SortValues == nil || len(*src.SortValues) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(*src.SortValues)
+}
@@ -52,3 +52,13 @@ func (src *ResultsProcessDefinition) ToStable() processdefinition.ProcessDefinit
 		}),
 	}
 }
+
+func (src *ChangeStatus) ToStable() processinstance.ChangeStatus {
+	if src == nil {
+		return processinstance.ChangeStatus{}
+	}
+	return processinstance.ChangeStatus{
+		Deleted: convert.Deref(src.Deleted, 0),
+		Message: convert.Deref(src.Message, ""),
+	}
+}
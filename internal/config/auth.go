@@ -3,28 +3,49 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 )
 
 type AuthMode string
 
-func (m AuthMode) IsValid() bool { return m == ModeOAuth2 || m == ModeCookie }
+func (m AuthMode) IsValid() bool { return slices.Contains(AuthModes, m) }
 
 const (
 	ModeOAuth2 AuthMode = "oauth2"
 	ModeCookie AuthMode = "cookie"
+	ModeToken  AuthMode = "token"
+	ModeBasic  AuthMode = "basic"
 )
 
+// AuthModes lists the supported authentication modes.
+var AuthModes = []AuthMode{ModeOAuth2, ModeCookie, ModeToken, ModeBasic}
+
+// InvalidModeError reports an unsupported authentication mode together with the supported ones.
+func InvalidModeError(m AuthMode) error {
+	allowed := make([]string, len(AuthModes))
+	for i, mode := range AuthModes {
+		allowed[i] = fmt.Sprintf("%q", mode)
+	}
+	return fmt.Errorf("invalid value %q (allowed values: %s)", m, strings.Join(allowed, ", "))
+}
+
 type Auth struct {
 	Mode   AuthMode                    `mapstructure:"mode"`
 	OAuth2 AuthOAuth2ClientCredentials `mapstructure:"oauth2"`
 	Cookie AuthCookieSession           `mapstructure:"cookie"`
+	Token  AuthStaticToken             `mapstructure:"token"`
+	Basic  AuthBasic                   `mapstructure:"basic"`
+
+	TokenCache TokenCache `mapstructure:"token_cache"`
 }
 
 func (c *Auth) Validate() error {
 	var errs []error
 	if !c.Mode.IsValid() {
-		errs = append(errs, fmt.Errorf("mode: invalid value %q (allowed values: %q, %q)", c.Mode, ModeOAuth2, ModeCookie))
+		errs = append(errs, fmt.Errorf("mode: %w", InvalidModeError(c.Mode)))
 	} else {
 		switch c.Mode {
 		case ModeOAuth2:
@@ -35,6 +56,14 @@ func (c *Auth) Validate() error {
 			if err := c.Cookie.Validate(); err != nil {
 				errs = append(errs, fmt.Errorf("cookie: %w", err))
 			}
+		case ModeToken:
+			if err := c.Token.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("token: %w", err))
+			}
+		case ModeBasic:
+			if err := c.Basic.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("basic: %w", err))
+			}
 		}
 	}
 	return errors.Join(errs...)
@@ -45,6 +74,20 @@ type AuthOAuth2ClientCredentials struct {
 	ClientID     string            `mapstructure:"client_id"`
 	ClientSecret string            `mapstructure:"client_secret"`
 	Scopes       map[string]string `mapstructure:"scopes"`
+
+	// private_key_jwt (RFC 7523): the client authenticates with a signed assertion instead of the secret
+	PrivateKeyFile  string `mapstructure:"private_key_file"` // PEM private key (PKCS#1, PKCS#8 or SEC 1)
+	KeyID           string `mapstructure:"key_id"`           // kid header, as registered with the identity provider
+	Algorithm       string `mapstructure:"algorithm"`        // RS256 (default), PS256 or ES256
+	CertificateFile string `mapstructure:"certificate_file"` // optional PEM certificate of the key, adds x5t headers (Entra ID)
+}
+
+// AssertionAlgorithms lists the supported signing algorithms of client assertions.
+var AssertionAlgorithms = []string{"RS256", "PS256", "ES256"}
+
+// UsesPrivateKeyJWT reports whether the client authenticates with a signed assertion.
+func (a *AuthOAuth2ClientCredentials) UsesPrivateKeyJWT() bool {
+	return strings.TrimSpace(a.PrivateKeyFile) != ""
 }
 
 var allowedScopeKeys = map[string]struct{}{CamundaApiKeyConst: {}, OperateApiKeyConst: {}, TasklistApiKeyConst: {}}
@@ -59,7 +102,16 @@ func (a *AuthOAuth2ClientCredentials) Validate() error {
 	if strings.TrimSpace(a.ClientID) == "" {
 		errs = append(errs, ErrNoClientID)
 	}
-	if strings.TrimSpace(a.ClientSecret) == "" {
+	switch {
+	case a.UsesPrivateKeyJWT():
+		if strings.TrimSpace(a.ClientSecret) != "" {
+			errs = append(errs, errors.New("client_secret and private_key_file are mutually exclusive"))
+		}
+		if a.Algorithm != "" && !slices.Contains(AssertionAlgorithms, a.Algorithm) {
+			errs = append(errs, fmt.Errorf("algorithm: invalid value %q (allowed values: %s)",
+				a.Algorithm, strings.Join(AssertionAlgorithms, ", ")))
+		}
+	case strings.TrimSpace(a.ClientSecret) == "":
 		errs = append(errs, ErrNoClientSecret)
 	}
 
@@ -101,3 +153,58 @@ func (c *AuthCookieSession) Validate() error {
 	}
 	return errors.Join(errs...)
 }
+
+// AuthStaticToken is a pre-minted bearer token, e.g. from CI. Exactly one of the sources must be set;
+// env and file are read at request time, so a rotated token is picked up without restart.
+type AuthStaticToken struct {
+	Value string `mapstructure:"value"`
+	Env   string `mapstructure:"env"`  // name of the environment variable holding the token
+	File  string `mapstructure:"file"` // path of a file holding the token
+}
+
+func (t *AuthStaticToken) Validate() error {
+	set := 0
+	for _, v := range []string{t.Value, t.Env, t.File} {
+		if strings.TrimSpace(v) != "" {
+			set++
+		}
+	}
+	switch set {
+	case 0:
+		return errors.New("no token source provided (set one of value, env, file)")
+	case 1:
+		return nil
+	default:
+		return errors.New("more than one token source provided (set only one of value, env, file)")
+	}
+}
+
+type AuthBasic struct {
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+func (b *AuthBasic) Validate() error {
+	if strings.TrimSpace(b.Username) == "" {
+		return errors.New("no username provided in basic auth configuration")
+	}
+	return nil
+}
+
+// TokenCache configures the on-disk cache of access tokens shared by all invocations.
+type TokenCache struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Dir     string `mapstructure:"dir"` // default $XDG_CACHE_HOME/camunder/tokens
+}
+
+// Directory returns the configured cache directory, or the default one below the user cache dir.
+func (c *TokenCache) Directory() (string, error) {
+	if c.Dir != "" {
+		return c.Dir, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("token cache dir: %w", err)
+	}
+	return filepath.Join(base, "camunder", "tokens"), nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		auth Auth
+		err  string
+	}{
+		{"token value", Auth{Mode: ModeToken, Token: AuthStaticToken{Value: "t"}}, ""},
+		{"token file", Auth{Mode: ModeToken, Token: AuthStaticToken{File: "/run/token"}}, ""},
+		{"token missing", Auth{Mode: ModeToken}, "no token source"},
+		{"token ambiguous", Auth{Mode: ModeToken, Token: AuthStaticToken{Value: "t", Env: "CI_TOKEN"}}, "more than one token source"},
+		{"basic", Auth{Mode: ModeBasic, Basic: AuthBasic{Username: "ci"}}, ""},
+		{"basic without username", Auth{Mode: ModeBasic}, "no username"},
+		{"private_key_jwt", Auth{Mode: ModeOAuth2, OAuth2: AuthOAuth2ClientCredentials{TokenURL: "u", ClientID: "c", PrivateKeyFile: "key.pem"}}, ""},
+		{"private_key_jwt with secret", Auth{Mode: ModeOAuth2, OAuth2: AuthOAuth2ClientCredentials{TokenURL: "u", ClientID: "c", ClientSecret: "s", PrivateKeyFile: "key.pem"}}, "mutually exclusive"},
+		{"private_key_jwt algorithm", Auth{Mode: ModeOAuth2, OAuth2: AuthOAuth2ClientCredentials{TokenURL: "u", ClientID: "c", PrivateKeyFile: "key.pem", Algorithm: "HS256"}}, "algorithm"},
+		{"oauth2 without credentials", Auth{Mode: ModeOAuth2, OAuth2: AuthOAuth2ClientCredentials{TokenURL: "u", ClientID: "c"}}, "no client_secret or private_key_file"},
+		{"unknown mode", Auth{Mode: "kerberos"}, `allowed values: "oauth2", "cookie", "token", "basic"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.auth.Validate()
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestHTTPValidate_TLS(t *testing.T) {
+	h := HTTP{Timeout: "30s", TLS: HTTPTLS{CertFile: "client.pem"}}
+	require.ErrorContains(t, h.Validate(), "cert_file and key_file must be set together")
+	h.TLS.KeyFile = "client-key.pem"
+	require.NoError(t, h.Validate())
+}
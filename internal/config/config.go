@@ -12,14 +12,15 @@ var (
 	ErrNoBaseURL      = errors.New("no base_url provided in api configuration")
 	ErrNoTokenURL     = errors.New("no token_url provided in auth configuration")
 	ErrNoClientID     = errors.New("no client_id provided in auth configuration")
-	ErrNoClientSecret = errors.New("no client_secret provided in auth configuration")
+	ErrNoClientSecret = errors.New("no client_secret or private_key_file provided in auth configuration")
 
 	ErrNoConfigInContext       = errors.New("no config in context")
 	ErrInvalidServiceInContext = errors.New("invalid config in context")
 )
 
 type Config struct {
-	Config string `mapstructure:"config"`
+	Config  string `mapstructure:"config"`
+	Profile string `mapstructure:"profile"` // effective profile, empty when the top-level settings are used
 
 	App  App  `mapstructure:"app"`
 	Auth Auth `mapstructure:"auth"`
@@ -30,6 +31,7 @@ type Config struct {
 func (c *Config) String() string {
 	var alias Config
 	alias.Config = c.Config
+	alias.Profile = c.Profile
 	alias.App = c.App
 	alias.HTTP = c.HTTP
 	alias.APIs.Version = c.APIs.Version
@@ -41,14 +43,27 @@ func (c *Config) String() string {
 	alias.APIs.Tasklist.Key = c.APIs.Tasklist.Key
 	alias.APIs.Tasklist.BaseURL = c.APIs.Tasklist.BaseURL
 
+	alias.Auth.Mode = c.Auth.Mode
 	alias.Auth.OAuth2.TokenURL = c.Auth.OAuth2.TokenURL
 	alias.Auth.OAuth2.ClientID = "******"
 	alias.Auth.OAuth2.ClientSecret = "******"
 	alias.Auth.OAuth2.Scopes = maps.Clone(c.Auth.OAuth2.Scopes)
+	alias.Auth.OAuth2.PrivateKeyFile = c.Auth.OAuth2.PrivateKeyFile
+	alias.Auth.OAuth2.KeyID = c.Auth.OAuth2.KeyID
+	alias.Auth.OAuth2.Algorithm = c.Auth.OAuth2.Algorithm
+	alias.Auth.OAuth2.CertificateFile = c.Auth.OAuth2.CertificateFile
 
 	alias.Auth.Cookie.BaseURL = c.Auth.Cookie.BaseURL
 	alias.Auth.Cookie.Username = "******"
 	alias.Auth.Cookie.Password = "******"
+	alias.Auth.Token.Env = c.Auth.Token.Env
+	alias.Auth.Token.File = c.Auth.Token.File
+	if c.Auth.Token.Value != "" {
+		alias.Auth.Token.Value = "******"
+	}
+	alias.Auth.Basic.Username = "******"
+	alias.Auth.Basic.Password = "******"
+	alias.Auth.TokenCache = c.Auth.TokenCache
 
 	b, err := json.MarshalIndent(alias, "", "  ")
 	if err != nil {
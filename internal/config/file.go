@@ -0,0 +1,109 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// readFileNode parses a YAML config file into its document node, keeping comments and key order.
+func readFileNode(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		// empty file
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parse %s: top level is not a mapping", path)
+	}
+	return &doc, nil
+}
+
+func writeFileNode(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), fi.Mode().Perm())
+}
+
+// lookupNode returns the value node of the dotted key, or nil if it does not exist.
+func lookupNode(m *yaml.Node, key string) *yaml.Node {
+	for _, part := range strings.Split(key, ".") {
+		if m == nil || m.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			if m.Content[i].Value == part {
+				next = m.Content[i+1]
+				break
+			}
+		}
+		m = next
+	}
+	return m
+}
+
+// SetFileValue sets the dotted key (e.g. http.timeout) to value in the YAML config file at path.
+// Missing mappings are created; comments and the order of the other keys are kept.
+func SetFileValue(path, key, value string) error {
+	if strings.TrimSpace(key) == "" {
+		return errors.New("empty key")
+	}
+	doc, err := readFileNode(path)
+	if err != nil {
+		return err
+	}
+	m := doc.Content[0]
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		var next *yaml.Node
+		for j := 0; j+1 < len(m.Content); j += 2 {
+			if m.Content[j].Value == part {
+				next = m.Content[j+1]
+				break
+			}
+		}
+		last := i == len(parts)-1
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			if last {
+				next = &yaml.Node{Kind: yaml.ScalarNode}
+			}
+			m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: part}, next)
+		}
+		if last {
+			if next.Kind != yaml.ScalarNode {
+				return fmt.Errorf("key %s is not a single value", key)
+			}
+			next.Value, next.Tag, next.Style = value, "", 0
+			break
+		}
+		if next.Kind != yaml.MappingNode {
+			return fmt.Errorf("key %s: %s is not a mapping", key, strings.Join(parts[:i+1], "."))
+		}
+		m = next
+	}
+	return writeFileNode(path, doc)
+}
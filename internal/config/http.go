@@ -1,17 +1,87 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/grafvonb/camunder/internal/services/common"
 )
 
 type HTTP struct {
-	Timeout string `mapstructure:"timeout"` // Go duration string, e.g., "30s"
+	Timeout string    `mapstructure:"timeout"` // Go duration string, e.g., "30s"
+	TLS     HTTPTLS   `mapstructure:"tls"`
+	Retry   HTTPRetry `mapstructure:"retry"`
+
+	// Client-side limits, applied to each API (camunda_api, operate_api, tasklist_api) separately.
+	RateLimit     float64 `mapstructure:"rate_limit"`     // requests per second, 0 for no limit
+	Burst         int     `mapstructure:"burst"`          // requests allowed at once above the rate
+	MaxConcurrent int     `mapstructure:"max_concurrent"` // requests in flight, 0 for no limit
+
+	// Offline testing: record all requests to, or serve them from, the files of a dir (flags only).
+	Record string `mapstructure:"record"`
+	Replay string `mapstructure:"replay"`
 }
 
 func (h *HTTP) Validate() error {
+	var errs []error
 	if strings.TrimSpace(h.Timeout) == "" {
-		return fmt.Errorf("timeout must not be empty")
+		errs = append(errs, fmt.Errorf("timeout must not be empty"))
+	}
+	if err := h.TLS.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("tls: %w", err))
+	}
+	if err := h.Retry.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("retry: %w", err))
+	}
+	if h.RateLimit < 0 {
+		errs = append(errs, errors.New("rate_limit must be non-negative"))
+	}
+	if h.Burst < 0 {
+		errs = append(errs, errors.New("burst must be non-negative"))
+	}
+	if h.MaxConcurrent < 0 {
+		errs = append(errs, errors.New("max_concurrent must be non-negative"))
+	}
+	if h.Record != "" && h.Replay != "" {
+		errs = append(errs, errors.New("record and replay are mutually exclusive"))
+	}
+	return errors.Join(errs...)
+}
+
+// HTTPTLS configures client certificates (mTLS) and additional trusted CAs for all API and token requests.
+type HTTPTLS struct {
+	CertFile           string `mapstructure:"cert_file"`            // PEM client certificate
+	KeyFile            string `mapstructure:"key_file"`             // PEM private key of the client certificate
+	CAFile             string `mapstructure:"ca_file"`              // PEM bundle trusted in addition to the system CAs
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"` // for tests only
+}
+
+// IsSet reports whether any TLS setting differs from Go's defaults.
+func (t *HTTPTLS) IsSet() bool {
+	return t.CertFile != "" || t.KeyFile != "" || t.CAFile != "" || t.InsecureSkipVerify
+}
+
+func (t *HTTPTLS) Validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return errors.New("cert_file and key_file must be set together")
 	}
 	return nil
 }
+
+// HTTPRetry configures the retries of requests failing with a transient error: a reset or refused
+// connection, or 429, 502, 503 or 504. max_retries 0 disables retries; timeout bounds the total
+// time spent waiting between the attempts of one request.
+type HTTPRetry struct {
+	common.BackoffConfig `mapstructure:",squash"`
+	NonIdempotent        bool `mapstructure:"non_idempotent"` // also retry e.g. POST and PATCH, which may run twice
+}
+
+func (r *HTTPRetry) Enabled() bool { return r.MaxRetries > 0 }
+
+func (r *HTTPRetry) Validate() error {
+	if !r.Enabled() {
+		return nil
+	}
+	return r.BackoffConfig.Validate()
+}
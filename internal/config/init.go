@@ -0,0 +1,125 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// InitOptions are the values of a config file generated by `config init`.
+type InitOptions struct {
+	Mode            AuthMode
+	APIsVersion     string
+	Tenant          string
+	CamundaBaseURL  string
+	OperateBaseURL  string
+	TasklistBaseURL string
+
+	// oauth2
+	TokenURL string
+	ClientID string
+
+	// cookie
+	CookieBaseURL  string
+	CookieUsername string
+
+	// basic
+	BasicUsername string
+}
+
+// DefaultInitOptions returns the defaults for the auth mode: Camunda 8 Run for cookie,
+// a local Camunda 8 with Keycloak for oauth2.
+func DefaultInitOptions(mode AuthMode) InitOptions {
+	if mode == ModeCookie {
+		return InitOptions{
+			Mode:            ModeCookie,
+			APIsVersion:     "8.7",
+			CamundaBaseURL:  "http://localhost:8080/v2",
+			OperateBaseURL:  "http://localhost:8080",
+			TasklistBaseURL: "http://localhost:8080",
+			CookieBaseURL:   "http://localhost:8080",
+			CookieUsername:  "demo",
+		}
+	}
+	return InitOptions{
+		Mode:            ModeOAuth2,
+		APIsVersion:     "8.7",
+		CamundaBaseURL:  "http://localhost:8080/v2",
+		OperateBaseURL:  "http://localhost:8081",
+		TasklistBaseURL: "http://localhost:8082",
+		TokenURL:        "http://localhost:18080/auth/realms/camunda-platform/protocol/openid-connect",
+		ClientID:        "camunder",
+	}
+}
+
+var initTemplate = template.Must(template.New("config").Parse(`# generated by camunder config init
+{{- if .Tenant }}
+app:
+  tenant: "{{ .Tenant }}"
+{{- end }}
+
+auth:
+  mode: "{{ .Mode }}"
+{{- if eq .Mode "oauth2" }}
+  oauth2:
+    token_url: "{{ .TokenURL }}"
+    client_id: "{{ .ClientID }}"
+    # set the secret with the environment variable CAMUNDER_AUTH_OAUTH2_CLIENT_SECRET
+    client_secret: ""
+{{- else if eq .Mode "cookie" }}
+  cookie:
+    base_url: "{{ .CookieBaseURL }}"
+    username: "{{ .CookieUsername }}"
+    # set the password with the environment variable CAMUNDER_AUTH_COOKIE_PASSWORD
+    password: ""
+{{- else if eq .Mode "token" }}
+  token:
+    # set the token with the environment variable CAMUNDER_AUTH_TOKEN_VALUE,
+    # or read it at request time from another variable (env) or a file (file)
+    value: ""
+{{- else if eq .Mode "basic" }}
+  basic:
+    username: "{{ .BasicUsername }}"
+    # set the password with the environment variable CAMUNDER_AUTH_BASIC_PASSWORD
+    password: ""
+{{- end }}
+
+http:
+  timeout: "30s"
+
+apis:
+  version: "{{ .APIsVersion }}"
+  camunda_api:
+    base_url: "{{ .CamundaBaseURL }}"
+  operate_api:
+    base_url: "{{ .OperateBaseURL }}"
+  tasklist_api:
+    base_url: "{{ .TasklistBaseURL }}"
+`))
+
+// RenderInitFile renders the config file for the options.
+func RenderInitFile(o InitOptions) ([]byte, error) {
+	if !o.Mode.IsValid() {
+		return nil, fmt.Errorf("mode: %w", InvalidModeError(o.Mode))
+	}
+	var buf bytes.Buffer
+	if err := initTemplate.Execute(&buf, o); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DefaultConfigPath is where `config init` writes when no path is given:
+// $XDG_CONFIG_HOME/camunder/config.yaml, or $HOME/.config/camunder/config.yaml.
+func DefaultConfigPath() (string, error) {
+	if xdg, ok := os.LookupEnv("XDG_CONFIG_HOME"); ok && xdg != "" {
+		return filepath.Join(xdg, "camunder", "config.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "camunder", "config.yaml"), nil
+}
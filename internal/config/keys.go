@@ -0,0 +1,79 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// runtimeKeys are set by camunder itself or by flags only and cannot be configured in a file.
+var runtimeKeys = []string{"config", "profile", "apis.camunda_api.key", "apis.operate_api.key", "apis.tasklist_api.key",
+	"http.record", "http.replay"}
+
+// Keys returns the sorted dotted keys of all settings, e.g. auth.oauth2.token_url.
+// Map settings (auth.oauth2.scopes) are returned as the key of the map.
+func Keys() []string {
+	var keys []string
+	collectKeys(reflect.TypeOf(Config{}), "", &keys)
+	keys = slices.DeleteFunc(keys, func(k string) bool { return slices.Contains(runtimeKeys, k) })
+	slices.Sort(keys)
+	return keys
+}
+
+func collectKeys(t reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if opts == "squash" && f.Type.Kind() == reflect.Struct {
+			collectKeys(f.Type, prefix, keys)
+			continue
+		}
+		if tag == "" || tag == "-" {
+			continue
+		}
+		key := prefix + tag
+		if f.Type.Kind() == reflect.Struct {
+			collectKeys(f.Type, key+".", keys)
+			continue
+		}
+		*keys = append(*keys, key)
+	}
+}
+
+// IsKnownKey reports whether key can be set in a config file: a setting, an entry of a map setting,
+// current_profile, or any of these within a profile (profiles.<name>.<key>).
+func IsKnownKey(key string) bool {
+	key = strings.ToLower(key)
+	if key == CurrentProfileKey {
+		return true
+	}
+	if rest, ok := strings.CutPrefix(key, ProfilesKey+"."); ok {
+		_, key, ok = strings.Cut(rest, ".")
+		if !ok {
+			return false
+		}
+	}
+	for _, k := range Keys() {
+		if key == k || strings.HasPrefix(key, k+".") && isMapKey(k) {
+			return true
+		}
+	}
+	return false
+}
+
+func isMapKey(key string) bool {
+	t := reflect.TypeOf(Config{})
+	for _, part := range strings.Split(key, ".") {
+		found := false
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).Tag.Get("mapstructure") == part {
+				t, found = t.Field(i).Type, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return t.Kind() == reflect.Map
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeys(t *testing.T) {
+	keys := Keys()
+	require.Contains(t, keys, "auth.oauth2.token_url")
+	require.Contains(t, keys, "app.backoff.max_retries")
+	require.NotContains(t, keys, "config")
+	require.NotContains(t, keys, "apis.camunda_api.key")
+}
+
+func TestIsKnownKey(t *testing.T) {
+	for _, k := range []string{"http.timeout", "auth.oauth2.scopes.operate_api", "current_profile", "profiles.prod.apis.version"} {
+		require.True(t, IsKnownKey(k), k)
+	}
+	for _, k := range []string{"", "http", "http.timeouts", "apis.camunda_api.key", "profiles.prod", "profiles.prod.nope", "auth.mode.x"} {
+		require.False(t, IsKnownKey(k), k)
+	}
+}
+
+func TestRenderInitFile(t *testing.T) {
+	data, err := RenderInitFile(DefaultInitOptions(ModeOAuth2))
+	require.NoError(t, err)
+	require.Contains(t, string(data), `mode: "oauth2"`)
+	require.Contains(t, string(data), `client_id: "camunder"`)
+	require.NotContains(t, string(data), "cookie:")
+
+	data, err = RenderInitFile(InitOptions{Mode: ModeBasic, BasicUsername: "ci"})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `username: "ci"`)
+
+	_, err = RenderInitFile(InitOptions{Mode: "kerberos"})
+	require.Error(t, err)
+}
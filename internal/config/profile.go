@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+const (
+	// ProfilesKey holds the named profiles of a config file. A profile has the same sections
+	// as the top level (app, auth, apis, http) and overrides the top-level values it sets.
+	ProfilesKey = "profiles"
+	// CurrentProfileKey names the profile used when neither --profile nor CAMUNDER_PROFILE is set.
+	CurrentProfileKey = "current_profile"
+)
+
+// ReadProfiles returns the sorted profile names of the config file at path and its current_profile.
+func ReadProfiles(path string) (names []string, current string, err error) {
+	doc, err := readFileNode(path)
+	if err != nil {
+		return nil, "", err
+	}
+	root := doc.Content[0]
+	if n := lookupNode(root, CurrentProfileKey); n != nil {
+		current = n.Value
+	}
+	if p := lookupNode(root, ProfilesKey); p != nil {
+		for i := 0; i+1 < len(p.Content); i += 2 {
+			names = append(names, p.Content[i].Value)
+		}
+	}
+	slices.Sort(names)
+	return names, current, nil
+}
+
+// SetCurrentProfile makes name the current_profile of the config file at path.
+func SetCurrentProfile(path, name string) error {
+	names, _, err := ReadProfiles(path)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(names, name) {
+		return fmt.Errorf("profile %q not found in %s (available: %v)", name, path, names)
+	}
+	return SetFileValue(path, CurrentProfileKey, name)
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const profilesYAML = `# shared settings
+apis:
+  version: "8.7"
+
+current_profile: dev
+profiles:
+  dev:
+    apis:
+      camunda_api:
+        base_url: "http://localhost:8080/v2"
+  prod:
+    apis:
+      camunda_api:
+        base_url: "https://camunda.example.com/v2" # production
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestReadProfiles(t *testing.T) {
+	names, current, err := ReadProfiles(writeConfig(t, profilesYAML))
+	require.NoError(t, err)
+	require.Equal(t, []string{"dev", "prod"}, names)
+	require.Equal(t, "dev", current)
+}
+
+func TestSetCurrentProfile(t *testing.T) {
+	path := writeConfig(t, profilesYAML)
+	require.NoError(t, SetCurrentProfile(path, "prod"))
+	require.Error(t, SetCurrentProfile(path, "staging"))
+
+	_, current, err := ReadProfiles(path)
+	require.NoError(t, err)
+	require.Equal(t, "prod", current)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "# shared settings")
+	require.Contains(t, string(data), "# production")
+}
+
+func TestSetFileValue_CreatesMissingKeys(t *testing.T) {
+	path := writeConfig(t, "")
+	require.NoError(t, SetFileValue(path, "http.timeout", "45s"))
+	_, current, err := ReadProfiles(path)
+	require.NoError(t, err)
+	require.Empty(t, current)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "http:\n  timeout: 45s\n", string(data))
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Secret values can reference their source instead of holding the secret itself:
+//
+//	file:///run/secrets/camunder   content of the file (also file:relative/path)
+//	cmd:pass show camunda/prod      standard output of the shell command
+//	env:OTHER_VAR                   value of the environment variable
+//	raw:file:abc                    the literal rest, for secrets that start with one of the prefixes
+//
+// References are resolved by ResolveSecrets only, so printing the config never reads nor runs anything.
+const (
+	secretFilePrefix = "file:"
+	secretCmdPrefix  = "cmd:"
+	secretEnvPrefix  = "env:"
+	secretRawPrefix  = "raw:"
+)
+
+// secretCmdTimeout bounds commands like password manager lookups, which may wait for input.
+const secretCmdTimeout = 30 * time.Second
+
+// IsSecretRef reports whether the value references a secret instead of holding it.
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, secretFilePrefix) ||
+		strings.HasPrefix(value, secretCmdPrefix) ||
+		strings.HasPrefix(value, secretEnvPrefix) ||
+		strings.HasPrefix(value, secretRawPrefix)
+}
+
+// ResolveSecret returns the secret the value references, or the value itself if it is no reference.
+// Surrounding whitespace, e.g. the trailing newline of a file or command output, is removed.
+func ResolveSecret(ctx context.Context, value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, secretRawPrefix):
+		return strings.TrimPrefix(value, secretRawPrefix), nil
+	case strings.HasPrefix(value, secretFilePrefix):
+		path, err := secretFilePath(value)
+		if err != nil {
+			return "", err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	case strings.HasPrefix(value, secretCmdPrefix):
+		return runSecretCmd(ctx, strings.TrimPrefix(value, secretCmdPrefix))
+	case strings.HasPrefix(value, secretEnvPrefix):
+		name := strings.TrimPrefix(value, secretEnvPrefix)
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return strings.TrimSpace(v), nil
+	default:
+		return value, nil
+	}
+}
+
+// CheckSecretRef reports problems of the reference that show without reading the file or running
+// the command: a missing file, an unset environment variable or an empty command.
+func CheckSecretRef(value string) error {
+	switch {
+	case strings.HasPrefix(value, secretFilePrefix):
+		path, err := secretFilePath(value)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stat(path)
+		return err
+	case strings.HasPrefix(value, secretCmdPrefix):
+		if strings.TrimSpace(strings.TrimPrefix(value, secretCmdPrefix)) == "" {
+			return errors.New("empty command")
+		}
+	case strings.HasPrefix(value, secretEnvPrefix):
+		name := strings.TrimPrefix(value, secretEnvPrefix)
+		if _, ok := os.LookupEnv(name); !ok {
+			return fmt.Errorf("environment variable %s is not set", name)
+		}
+	}
+	return nil
+}
+
+// secretFilePath returns the path of a file: or file:// reference.
+func secretFilePath(value string) (string, error) {
+	path := strings.TrimPrefix(value, secretFilePrefix)
+	if strings.HasPrefix(path, "//") {
+		u, err := url.Parse(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid file reference: %w", err)
+		}
+		path = u.Path
+	}
+	return path, nil
+}
+
+func runSecretCmd(ctx context.Context, command string) (string, error) {
+	if strings.TrimSpace(command) == "" {
+		return "", errors.New("empty command")
+	}
+	ctx, cancel := context.WithTimeout(ctx, secretCmdTimeout)
+	defer cancel()
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	c.Stdout, c.Stderr = &stdout, &stderr
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("command %q: %w: %s", command, err, msg)
+		}
+		return "", fmt.Errorf("command %q: %w", command, err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// ResolveSecrets replaces the secret references of the active auth mode by the secrets.
+// Secrets of the other modes are left alone, so that no command is run needlessly.
+func (c *Config) ResolveSecrets(ctx context.Context) error {
+	var errs []error
+	for key, value := range c.activeSecrets() {
+		if !IsSecretRef(*value) {
+			continue
+		}
+		resolved, err := ResolveSecret(ctx, *value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+		*value = resolved
+	}
+	return errors.Join(errs...)
+}
+
+// CheckSecrets reports the problems of the secret references of the active auth mode that show
+// without reading a file or running a command, see CheckSecretRef.
+func (c *Config) CheckSecrets() error {
+	var errs []error
+	for key, value := range c.activeSecrets() {
+		if err := CheckSecretRef(*value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// activeSecrets returns the secrets of the active auth mode by config key.
+func (c *Config) activeSecrets() map[string]*string {
+	switch c.Auth.Mode {
+	case ModeOAuth2, "":
+		return map[string]*string{"auth.oauth2.client_secret": &c.Auth.OAuth2.ClientSecret}
+	case ModeCookie:
+		return map[string]*string{"auth.cookie.password": &c.Auth.Cookie.Password}
+	case ModeToken:
+		return map[string]*string{"auth.token.value": &c.Auth.Token.Value}
+	case ModeBasic:
+		return map[string]*string{"auth.basic.password": &c.Auth.Basic.Password}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecret(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(file, []byte("from-file\n"), 0o600))
+	t.Setenv("CAMUNDER_TEST_SECRET", "from-env")
+
+	tests := []struct {
+		value string
+		want  string
+		err   string
+	}{
+		{value: "plain", want: "plain"},
+		{value: "file://" + file, want: "from-file"},
+		{value: "file:" + file, want: "from-file"},
+		{value: "env:CAMUNDER_TEST_SECRET", want: "from-env"},
+		{value: "env:CAMUNDER_TEST_SECRET_UNSET", err: "is not set"},
+		{value: "file://" + file + ".missing", err: "no such file"},
+		{value: "cmd:", err: "empty command"},
+		{value: "raw:file:not-a-reference", want: "file:not-a-reference"},
+		{value: "raw:raw:", want: "raw:"},
+	}
+	if runtime.GOOS != "windows" {
+		tests = append(tests,
+			struct{ value, want, err string }{value: "cmd:echo from-cmd", want: "from-cmd"},
+			struct{ value, want, err string }{value: "cmd:echo denied >&2; exit 3", err: "denied"},
+		)
+	}
+	for _, tt := range tests {
+		got, err := ResolveSecret(ctx, tt.value)
+		if tt.err != "" {
+			require.ErrorContains(t, err, tt.err, tt.value)
+			continue
+		}
+		require.NoError(t, err, tt.value)
+		require.Equal(t, tt.want, got, tt.value)
+	}
+}
+
+func TestResolveSecrets_OnlyActiveMode(t *testing.T) {
+	t.Setenv("CAMUNDER_TEST_SECRET", "s3cret")
+	c := Config{Auth: Auth{
+		Mode:   ModeOAuth2,
+		OAuth2: AuthOAuth2ClientCredentials{ClientSecret: "env:CAMUNDER_TEST_SECRET"},
+		Cookie: AuthCookieSession{Password: "cmd:exit 1"},
+	}}
+	require.NoError(t, c.ResolveSecrets(context.Background()))
+	require.Equal(t, "s3cret", c.Auth.OAuth2.ClientSecret)
+	require.Equal(t, "cmd:exit 1", c.Auth.Cookie.Password)
+}
+
+func TestCheckSecrets(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(file, []byte("from-file\n"), 0o600))
+	t.Setenv("CAMUNDER_TEST_SECRET", "from-env")
+
+	for _, ok := range []string{"plain", "file://" + file, "env:CAMUNDER_TEST_SECRET", "cmd:exit 1", "raw:env:NOPE"} {
+		require.NoError(t, CheckSecretRef(ok), ok)
+	}
+	for _, bad := range []string{"file:" + file + ".missing", "env:CAMUNDER_TEST_SECRET_UNSET", "cmd: "} {
+		require.Error(t, CheckSecretRef(bad), bad)
+	}
+
+	c := Config{Auth: Auth{Mode: ModeBasic, Basic: AuthBasic{Password: "env:CAMUNDER_TEST_SECRET_UNSET"}}}
+	require.ErrorContains(t, c.CheckSecrets(), "auth.basic.password: environment variable CAMUNDER_TEST_SECRET_UNSET is not set")
+}
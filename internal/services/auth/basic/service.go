@@ -0,0 +1,42 @@
+package basic
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/grafvonb/camunder/internal/config"
+	authcore "github.com/grafvonb/camunder/internal/services/auth/core"
+	"github.com/grafvonb/camunder/internal/services/common"
+)
+
+var _ authcore.Authenticator = (*Service)(nil)
+
+// Service sends HTTP basic credentials with every request, e.g. for an ingress in front of the APIs.
+type Service struct {
+	cfg *config.AuthBasic
+	log *slog.Logger
+}
+
+func New(cfg *config.Config, log *slog.Logger) (*Service, error) {
+	if cfg == nil {
+		return nil, errors.New("cfg is nil")
+	}
+	cfg.APIs.Operate.BaseURL = common.DefaultVal(cfg.APIs.Operate.BaseURL, cfg.APIs.Camunda.BaseURL)
+	cfg.APIs.Tasklist.BaseURL = common.DefaultVal(cfg.APIs.Tasklist.BaseURL, cfg.APIs.Camunda.BaseURL)
+	return &Service{cfg: &cfg.Auth.Basic, log: log}, nil
+}
+
+func (s *Service) Name() string                 { return "basic" }
+func (s *Service) Init(_ context.Context) error { return nil }
+
+func (s *Service) Editor() authcore.RequestEditor {
+	return func(_ context.Context, req *http.Request) error {
+		req.SetBasicAuth(s.cfg.Username, s.cfg.Password)
+		return nil
+	}
+}
+
+// ClearCache is a no-op, there is nothing to renew.
+func (s *Service) ClearCache() {}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 )
 
 var (
@@ -40,3 +41,11 @@ func FromContext(ctx context.Context) (Authenticator, error) {
 	}
 	return s, nil
 }
+
+// TokenStore persists access tokens beyond the lifetime of a single process. Keys are opaque
+// strings built by the authenticator from everything the token depends on.
+type TokenStore interface {
+	Load(key string) (token string, refreshAt time.Time, ok bool)
+	Save(key, token string, refreshAt time.Time) error
+	Delete(key string) error
+}
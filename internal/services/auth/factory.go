@@ -6,17 +6,36 @@ import (
 	"net/http"
 
 	"github.com/grafvonb/camunder/internal/config"
+	"github.com/grafvonb/camunder/internal/services/auth/basic"
 	"github.com/grafvonb/camunder/internal/services/auth/cookie"
 	"github.com/grafvonb/camunder/internal/services/auth/core"
 	"github.com/grafvonb/camunder/internal/services/auth/oauth2"
+	"github.com/grafvonb/camunder/internal/services/auth/token"
+	"github.com/grafvonb/camunder/internal/services/auth/tokencache"
 )
 
 func BuildAuthenticator(cfg *config.Config, httpClient *http.Client, log *slog.Logger) (core.Authenticator, error) {
 	switch cfg.Auth.Mode {
 	case config.ModeOAuth2, "":
-		return oauth2.New(cfg, httpClient, log)
+		var opts []oauth2.Option
+		if cfg.Auth.TokenCache.Enabled {
+			dir, err := cfg.Auth.TokenCache.Directory()
+			if err != nil {
+				return nil, err
+			}
+			store, err := tokencache.NewFileStore(dir)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, oauth2.WithTokenStore(store))
+		}
+		return oauth2.New(cfg, httpClient, log, opts...)
 	case config.ModeCookie:
 		return cookie.New(cfg, httpClient, log)
+	case config.ModeToken:
+		return token.New(cfg, log)
+	case config.ModeBasic:
+		return basic.New(cfg, log)
 	default:
 		return nil, fmt.Errorf("unknown auth mode: %s", cfg.Auth.Mode)
 	}
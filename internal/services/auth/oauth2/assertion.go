@@ -0,0 +1,176 @@
+package oauth2
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // x5t is defined as the SHA-1 thumbprint
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grafvonb/camunder/internal/config"
+)
+
+const (
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	// assertionLifetime is kept short, a new assertion is signed for every token request.
+	assertionLifetime = 2 * time.Minute
+)
+
+// assertionSigner signs the client assertions of private_key_jwt client authentication (RFC 7523).
+type assertionSigner struct {
+	key    crypto.Signer
+	alg    string
+	header map[string]string
+	now    func() time.Time
+}
+
+func newAssertionSigner(o config.AuthOAuth2ClientCredentials) (*assertionSigner, error) {
+	key, err := readPrivateKey(o.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	alg := o.Algorithm
+	if alg == "" {
+		alg = "RS256"
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if alg != "RS256" && alg != "PS256" {
+			return nil, fmt.Errorf("algorithm %s does not match the RSA key in %s", alg, o.PrivateKeyFile)
+		}
+	case *ecdsa.PrivateKey:
+		if alg != "ES256" || k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("algorithm %s does not match the EC key in %s (ES256 requires a P-256 key)", alg, o.PrivateKeyFile)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type %T in %s", key, o.PrivateKeyFile)
+	}
+
+	header := map[string]string{"alg": alg, "typ": "JWT"}
+	if o.KeyID != "" {
+		header["kid"] = o.KeyID
+	}
+	if o.CertificateFile != "" {
+		der, err := readCertificate(o.CertificateFile)
+		if err != nil {
+			return nil, err
+		}
+		s1, s256 := sha1.Sum(der), sha256.Sum256(der) //nolint:gosec // see import
+		header["x5t"] = base64.RawURLEncoding.EncodeToString(s1[:])
+		header["x5t#S256"] = base64.RawURLEncoding.EncodeToString(s256[:])
+	}
+	return &assertionSigner{key: key, alg: alg, header: header, now: time.Now}, nil
+}
+
+// sign returns a compact JWS issued by and about the client, for the token endpoint as audience.
+func (a *assertionSigner) sign(clientID, audience string) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	now := a.now()
+	claims := map[string]any{
+		"iss": clientID,
+		"sub": clientID,
+		"aud": audience,
+		"jti": hex.EncodeToString(jti),
+		"iat": now.Unix(),
+		"nbf": now.Unix(),
+		"exp": now.Add(assertionLifetime).Unix(),
+	}
+	h, err := json.Marshal(a.header)
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	sig, err := a.signature([]byte(input))
+	if err != nil {
+		return "", fmt.Errorf("sign client assertion: %w", err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func (a *assertionSigner) signature(input []byte) ([]byte, error) {
+	digest := sha256.Sum256(input)
+	switch a.alg {
+	case "RS256":
+		return a.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	case "PS256":
+		return a.key.Sign(rand.Reader, digest[:], &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256})
+	case "ES256":
+		// JWS uses the fixed-size r||s encoding instead of ASN.1
+		r, s, err := ecdsa.Sign(rand.Reader, a.key.(*ecdsa.PrivateKey), digest[:])
+		if err != nil {
+			return nil, err
+		}
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig, nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %s", a.alg)
+	}
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	var key any
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse private key %s: %w", path, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T in %s", key, path)
+	}
+	return signer, nil
+}
+
+func readCertificate(path string) ([]byte, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no certificate in %s", path)
+	}
+	return block.Bytes, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data in " + path)
+	}
+	return block, nil
+}
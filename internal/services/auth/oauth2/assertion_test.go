@@ -0,0 +1,93 @@
+package oauth2
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafvonb/camunder/internal/config"
+	"github.com/grafvonb/camunder/internal/testx"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKey(t *testing.T, key crypto.Signer) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+	return path
+}
+
+func TestRetrieveTokenForAPI_PrivateKeyJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		alg     string
+		key     crypto.Signer
+		trusted crypto.PublicKey
+		err     string
+	}{
+		{alg: "", key: rsaKey, trusted: &rsaKey.PublicKey},
+		{alg: "PS256", key: rsaKey, trusted: &rsaKey.PublicKey},
+		{alg: "ES256", key: ecKey, trusted: &ecKey.PublicKey},
+		{alg: "RS256", key: otherKey, trusted: &rsaKey.PublicKey, err: "invalid RS256 signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			srv := testx.StartAuthServerOAuth2(t, testx.OAuth2AuthOpts{PublicKey: tt.trusted})
+			defer srv.Close()
+
+			cfg := &config.Config{Auth: config.Auth{OAuth2: config.AuthOAuth2ClientCredentials{
+				TokenURL:       srv.TokenURL,
+				ClientID:       "camunder",
+				PrivateKeyFile: writeKey(t, tt.key),
+				KeyID:          "camunder-1",
+				Algorithm:      tt.alg,
+			}}}
+			s, err := New(cfg, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			require.NoError(t, err)
+
+			tok, err := s.RetrieveTokenForAPI(context.Background(), "camunda")
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "token-1", tok)
+
+			// every token request signs a new assertion, a replayed one is rejected by the server
+			s.ClearCache()
+			_, err = s.RetrieveTokenForAPI(context.Background(), "camunda")
+			require.NoError(t, err)
+			require.Equal(t, 2, srv.Issued())
+		})
+	}
+}
+
+func TestNew_PrivateKeyJWTAlgorithmMismatch(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	cfg := &config.Config{Auth: config.Auth{OAuth2: config.AuthOAuth2ClientCredentials{
+		TokenURL:       "http://localhost/token",
+		ClientID:       "camunder",
+		PrivateKeyFile: writeKey(t, ecKey),
+	}}}
+	_, err = New(cfg, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.ErrorContains(t, err, "algorithm RS256 does not match the EC key")
+}
@@ -6,20 +6,37 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net/http"
 	"net/url"
 	"path"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	client "github.com/grafvonb/camunder/internal/api/gen/clients/auth/oauth2"
 	"github.com/grafvonb/camunder/internal/config"
 	authcore "github.com/grafvonb/camunder/internal/services/auth/core"
 	"github.com/grafvonb/camunder/internal/services/common"
+	"github.com/grafvonb/camunder/internal/services/httpc"
 )
 
+// tokenExpirySkew is how long before its expiry a cached token is renewed, so that a request
+// started with it does not reach the server with an already expired token.
+const tokenExpirySkew = 30 * time.Second
+
 type TargetResolver func(*http.Request) string
 
+type cachedToken struct {
+	value     string
+	refreshAt time.Time // zero when the token server did not report an expiry
+}
+
+func (t cachedToken) valid(now time.Time) bool {
+	return t.value != "" && (t.refreshAt.IsZero() || now.Before(t.refreshAt))
+}
+
 type Service struct {
 	c          GenAuthClient
 	cfg        *config.Config
@@ -29,9 +46,12 @@ type Service struct {
 	prefix     string
 
 	tokenURL *url.URL
+	signer   *assertionSigner // nil when the client authenticates with its secret
 
 	mu    sync.Mutex
-	cache map[string]string
+	cache map[string]cachedToken
+	store authcore.TokenStore // optional, shares tokens across invocations
+	now   func() time.Time
 }
 
 type Option func(*Service)
@@ -48,6 +68,11 @@ func WithAuthHeader(name, prefix string) Option {
 	return func(s *Service) { s.headerName, s.prefix = name, prefix }
 }
 
+// WithTokenStore Keep tokens in the store too, so that later invocations can reuse them until they expire
+func WithTokenStore(ts authcore.TokenStore) Option {
+	return func(s *Service) { s.store = ts }
+}
+
 func New(cfg *config.Config, apiHTTP *http.Client, log *slog.Logger, opts ...Option) (*Service, error) {
 	if cfg == nil {
 		return nil, errors.New("cfg is nil")
@@ -61,7 +86,11 @@ func New(cfg *config.Config, apiHTTP *http.Client, log *slog.Logger, opts ...Opt
 	if err != nil {
 		return nil, fmt.Errorf("parse token url: %w", err)
 	}
-	tokenHTTP := &http.Client{Timeout: apiHTTP.Timeout} // no wrapped Transport, no Jar
+	transport, err := httpc.NewTransport(cfg.HTTP)
+	if err != nil {
+		return nil, err
+	}
+	tokenHTTP := &http.Client{Timeout: apiHTTP.Timeout, Transport: transport} // no wrapped Transport, no Jar
 
 	cfg.APIs.Operate.BaseURL = common.DefaultVal(cfg.APIs.Operate.BaseURL, cfg.APIs.Camunda.BaseURL)
 	cfg.APIs.Tasklist.BaseURL = common.DefaultVal(cfg.APIs.Tasklist.BaseURL, cfg.APIs.Camunda.BaseURL)
@@ -79,7 +108,13 @@ func New(cfg *config.Config, apiHTTP *http.Client, log *slog.Logger, opts ...Opt
 		headerName: "Authorization",
 		prefix:     "Bearer ",
 		tokenURL:   tu,
-		cache:      make(map[string]string),
+		cache:      make(map[string]cachedToken),
+		now:        time.Now,
+	}
+	if cfg.Auth.OAuth2.UsesPrivateKeyJWT() {
+		if s.signer, err = newAssertionSigner(cfg.Auth.OAuth2); err != nil {
+			return nil, fmt.Errorf("private_key_jwt: %w", err)
+		}
 	}
 	for _, opt := range opts {
 		opt(s)
@@ -106,6 +141,11 @@ func (s *Service) Editor() authcore.RequestEditor {
 	}
 }
 
+// tokenEndpoint is the URL the generated client posts to, the token URL is its base.
+func (s *Service) tokenEndpoint() string {
+	return strings.TrimRight(s.tokenURL.String(), "/") + "/token"
+}
+
 func sameURL(a, b *url.URL) bool {
 	return strings.EqualFold(a.Scheme, b.Scheme) &&
 		strings.EqualFold(a.Host, b.Host) &&
@@ -114,8 +154,23 @@ func sameURL(a, b *url.URL) bool {
 
 func (s *Service) ClearCache() {
 	s.mu.Lock()
-	s.cache = make(map[string]string)
+	targets := slices.Collect(maps.Keys(s.cache))
+	s.cache = make(map[string]cachedToken)
 	s.mu.Unlock()
+	if s.store == nil {
+		return
+	}
+	for _, target := range targets {
+		if err := s.store.Delete(s.storeKey(target)); err != nil {
+			s.log.Debug(fmt.Sprintf("token cache: %v", err))
+		}
+	}
+}
+
+// storeKey identifies a token in the store by everything it was requested with.
+func (s *Service) storeKey(target string) string {
+	o := s.cfg.Auth.OAuth2
+	return strings.Join([]string{o.TokenURL, o.ClientID, o.Scope(target)}, "\x00")
 }
 
 func (s *Service) Token(ctx context.Context, target string) (string, error) {
@@ -127,40 +182,73 @@ func (s *Service) RetrieveTokenForAPI(ctx context.Context, target string) (strin
 		return "", errors.New("oauth2 service is nil (not wired)")
 	}
 	s.mu.Lock()
-	if tok, ok := s.cache[target]; ok && tok != "" {
+	if tok, ok := s.cache[target]; ok && tok.valid(s.now()) {
 		s.mu.Unlock()
-		return tok, nil
+		return tok.value, nil
 	}
 	s.mu.Unlock()
 
+	if s.store != nil {
+		if tok, refreshAt, ok := s.store.Load(s.storeKey(target)); ok {
+			s.mu.Lock()
+			s.cache[target] = cachedToken{value: tok, refreshAt: refreshAt}
+			s.mu.Unlock()
+			return tok, nil
+		}
+	}
+
 	scope := s.cfg.Auth.OAuth2.Scope(target)
-	tok, err := s.requestToken(ctx, s.cfg.Auth.OAuth2.ClientID, s.cfg.Auth.OAuth2.ClientSecret, scope)
+	issued := s.now()
+	tok, expiresIn, err := s.requestToken(ctx, scope)
 	if err != nil {
 		return "", fmt.Errorf("retrieve token for %s: %w", target, err)
 	}
 
+	refreshAt := refreshTime(issued, expiresIn)
 	s.mu.Lock()
-	s.cache[target] = tok
+	s.cache[target] = cachedToken{value: tok, refreshAt: refreshAt}
 	s.mu.Unlock()
+	if s.store != nil {
+		if err := s.store.Save(s.storeKey(target), tok, refreshAt); err != nil {
+			s.log.Debug(fmt.Sprintf("token cache: %v", err))
+		}
+	}
 	return tok, nil
 }
 
-func (s *Service) requestToken(ctx context.Context, clientID, clientSecret, scope string) (string, error) {
-	body := formBody(clientID, clientSecret, scope)
+// refreshTime returns when a token issued at the given time with the given lifetime should be renewed.
+// Short-lived tokens are renewed after half their lifetime instead of tokenExpirySkew before expiry.
+func refreshTime(issued time.Time, expiresIn time.Duration) time.Time {
+	if expiresIn <= 0 {
+		return time.Time{}
+	}
+	return issued.Add(expiresIn - min(tokenExpirySkew, expiresIn/2))
+}
+
+func (s *Service) requestToken(ctx context.Context, scope string) (string, time.Duration, error) {
+	o := s.cfg.Auth.OAuth2
+	body := formBody(o.ClientID, o.ClientSecret, scope)
+	if s.signer != nil {
+		assertion, err := s.signer.sign(o.ClientID, s.tokenEndpoint())
+		if err != nil {
+			return "", 0, err
+		}
+		body = assertionFormBody(o.ClientID, assertion, scope)
+	}
 	resp, err := s.c.RequestTokenWithBodyWithResponse(ctx, formContentType, body) // uses plain tokenHTTP
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
 	if resp == nil {
-		return "", errors.New("nil token response")
+		return "", 0, errors.New("nil token response")
 	}
 	if resp.StatusCode() < http.StatusOK || resp.StatusCode() >= http.StatusMultipleChoices {
-		return "", fmt.Errorf("token request failed: status=%d body=%s", resp.StatusCode(), string(resp.Body))
+		return "", 0, fmt.Errorf("token request failed: status=%d body=%s", resp.StatusCode(), string(resp.Body))
 	}
 	if resp.JSON200 == nil || resp.JSON200.AccessToken == "" {
-		return "", fmt.Errorf("missing access token in successful response (status=%d)", resp.StatusCode())
+		return "", 0, fmt.Errorf("missing access token in successful response (status=%d)", resp.StatusCode())
 	}
-	return resp.JSON200.AccessToken, nil
+	return resp.JSON200.AccessToken, time.Duration(resp.JSON200.ExpiresIn) * time.Second, nil
 }
 
 func formBody(clientID, clientSecret, scope string) io.Reader {
@@ -173,3 +261,16 @@ func formBody(clientID, clientSecret, scope string) io.Reader {
 	}
 	return strings.NewReader(f.Encode())
 }
+
+// assertionFormBody authenticates the client with a signed JWT instead of the secret (private_key_jwt).
+func assertionFormBody(clientID, assertion, scope string) io.Reader {
+	f := url.Values{}
+	f.Set("grant_type", "client_credentials")
+	f.Set("client_id", clientID)
+	f.Set("client_assertion_type", clientAssertionType)
+	f.Set("client_assertion", assertion)
+	if strings.TrimSpace(scope) != "" {
+		f.Set("scope", scope)
+	}
+	return strings.NewReader(f.Encode())
+}
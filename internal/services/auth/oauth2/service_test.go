@@ -0,0 +1,105 @@
+package oauth2
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafvonb/camunder/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetrieveTokenForAPI_RefreshesBeforeExpiry(t *testing.T) {
+	issued := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issued++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":300}`, issued)
+	}))
+	defer ts.Close()
+
+	cfg := &config.Config{Auth: config.Auth{OAuth2: config.AuthOAuth2ClientCredentials{TokenURL: ts.URL, ClientID: "id"}}}
+	s, err := New(cfg, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	tok, err := s.RetrieveTokenForAPI(ctx, "camunda")
+	require.NoError(t, err)
+	require.Equal(t, "token-1", tok)
+
+	now = now.Add(300*time.Second - tokenExpirySkew - time.Second)
+	tok, err = s.RetrieveTokenForAPI(ctx, "camunda")
+	require.NoError(t, err)
+	require.Equal(t, "token-1", tok, "token is still cached")
+
+	now = now.Add(time.Second)
+	tok, err = s.RetrieveTokenForAPI(ctx, "camunda")
+	require.NoError(t, err)
+	require.Equal(t, "token-2", tok, "token is renewed within the skew")
+
+	s.ClearCache()
+	tok, err = s.RetrieveTokenForAPI(ctx, "camunda")
+	require.NoError(t, err)
+	require.Equal(t, "token-3", tok)
+}
+
+func TestRefreshTime(t *testing.T) {
+	issued := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	require.True(t, refreshTime(issued, 0).IsZero())
+	require.Equal(t, issued.Add(270*time.Second), refreshTime(issued, 300*time.Second))
+	require.Equal(t, issued.Add(10*time.Second), refreshTime(issued, 20*time.Second))
+}
+
+type memStore map[string]cachedToken
+
+func (m memStore) Load(key string) (string, time.Time, bool) {
+	t, ok := m[key]
+	return t.value, t.refreshAt, ok
+}
+func (m memStore) Save(key, token string, refreshAt time.Time) error {
+	m[key] = cachedToken{value: token, refreshAt: refreshAt}
+	return nil
+}
+func (m memStore) Delete(key string) error { delete(m, key); return nil }
+
+func TestRetrieveTokenForAPI_SharesTokensThroughStore(t *testing.T) {
+	issued := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issued++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":300}`, issued)
+	}))
+	defer ts.Close()
+
+	store := memStore{}
+	newService := func(clientID string) *Service {
+		cfg := &config.Config{Auth: config.Auth{OAuth2: config.AuthOAuth2ClientCredentials{TokenURL: ts.URL, ClientID: clientID}}}
+		s, err := New(cfg, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), WithTokenStore(store))
+		require.NoError(t, err)
+		return s
+	}
+	ctx := context.Background()
+
+	tok, err := newService("id").RetrieveTokenForAPI(ctx, "camunda")
+	require.NoError(t, err)
+	require.Equal(t, "token-1", tok)
+
+	second := newService("id")
+	tok, err = second.RetrieveTokenForAPI(ctx, "camunda")
+	require.NoError(t, err)
+	require.Equal(t, "token-1", tok, "a later invocation reuses the stored token")
+
+	tok, err = newService("other").RetrieveTokenForAPI(ctx, "camunda")
+	require.NoError(t, err)
+	require.Equal(t, "token-2", tok, "tokens are keyed by client ID")
+
+	second.ClearCache()
+	require.Len(t, store, 1)
+}
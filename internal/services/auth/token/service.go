@@ -0,0 +1,84 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/grafvonb/camunder/internal/config"
+	authcore "github.com/grafvonb/camunder/internal/services/auth/core"
+	"github.com/grafvonb/camunder/internal/services/common"
+)
+
+var (
+	_ authcore.Authenticator  = (*Service)(nil)
+	_ authcore.BearerProvider = (*Service)(nil)
+)
+
+// Service sends a pre-minted bearer token. Tokens from env or file are read for every request,
+// so a token rotated by the environment is used without restart.
+type Service struct {
+	cfg *config.AuthStaticToken
+	log *slog.Logger
+}
+
+func New(cfg *config.Config, log *slog.Logger) (*Service, error) {
+	if cfg == nil {
+		return nil, errors.New("cfg is nil")
+	}
+	cfg.APIs.Operate.BaseURL = common.DefaultVal(cfg.APIs.Operate.BaseURL, cfg.APIs.Camunda.BaseURL)
+	cfg.APIs.Tasklist.BaseURL = common.DefaultVal(cfg.APIs.Tasklist.BaseURL, cfg.APIs.Camunda.BaseURL)
+	return &Service{cfg: &cfg.Auth.Token, log: log}, nil
+}
+
+func (s *Service) Name() string { return "token" }
+
+// Init fails early when the token cannot be read.
+func (s *Service) Init(ctx context.Context) error {
+	_, err := s.Token(ctx, "")
+	return err
+}
+
+func (s *Service) Editor() authcore.RequestEditor {
+	return func(ctx context.Context, req *http.Request) error {
+		tok, err := s.Token(ctx, req.URL.Host)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+tok)
+		return nil
+	}
+}
+
+// ClearCache is a no-op, the token is never cached.
+func (s *Service) ClearCache() {}
+
+func (s *Service) Token(_ context.Context, _ string) (string, error) {
+	var tok string
+	switch {
+	case s.cfg.Value != "":
+		tok = s.cfg.Value
+	case s.cfg.Env != "":
+		tok = os.Getenv(s.cfg.Env)
+		if strings.TrimSpace(tok) == "" {
+			return "", fmt.Errorf("token: environment variable %s is empty or not set", s.cfg.Env)
+		}
+	case s.cfg.File != "":
+		b, err := os.ReadFile(s.cfg.File)
+		if err != nil {
+			return "", fmt.Errorf("token: %w", err)
+		}
+		tok = string(b)
+	default:
+		return "", errors.New("token: no token source configured")
+	}
+	tok = strings.TrimPrefix(strings.TrimSpace(tok), "Bearer ")
+	if tok == "" {
+		return "", errors.New("token: token is empty")
+	}
+	return tok, nil
+}
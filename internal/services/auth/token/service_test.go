@@ -0,0 +1,46 @@
+package token
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafvonb/camunder/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditor_ReadsTokenFileAtRequestTime(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(file, []byte("first\n"), 0o600))
+
+	cfg := &config.Config{Auth: config.Auth{Mode: config.ModeToken, Token: config.AuthStaticToken{File: file}}}
+	s, err := New(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, s.Init(ctx))
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/v2/topology", nil)
+	require.NoError(t, s.Editor()(ctx, req))
+	require.Equal(t, "Bearer first", req.Header.Get("Authorization"))
+
+	require.NoError(t, os.WriteFile(file, []byte("Bearer second"), 0o600))
+	require.NoError(t, s.Editor()(ctx, req))
+	require.Equal(t, "Bearer second", req.Header.Get("Authorization"))
+
+	require.NoError(t, os.Remove(file))
+	require.Error(t, s.Init(ctx))
+}
+
+func TestToken_FromEnv(t *testing.T) {
+	t.Setenv("CAMUNDER_TEST_CI_TOKEN", "from-env")
+	cfg := &config.Config{Auth: config.Auth{Mode: config.ModeToken, Token: config.AuthStaticToken{Env: "CAMUNDER_TEST_CI_TOKEN"}}}
+	s, err := New(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	tok, err := s.Token(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, "from-env", tok)
+}
@@ -0,0 +1,108 @@
+package tokencache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	authcore "github.com/grafvonb/camunder/internal/services/auth/core"
+)
+
+var _ authcore.TokenStore = (*FileStore)(nil)
+
+// FileStore keeps one file per token in a directory only the current user can access.
+// Entries are ignored once their refresh time has passed or when their permissions were widened.
+type FileStore struct {
+	dir string
+	now func() time.Time
+}
+
+type entry struct {
+	AccessToken string    `json:"access_token"`
+	RefreshAt   time.Time `json:"refresh_at,omitzero"`
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, errors.New("token cache dir is empty")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create token cache dir: %w", err)
+	}
+	return &FileStore{dir: dir, now: time.Now}, nil
+}
+
+// path hashes the key, so that neither client IDs nor URLs show up in file names.
+func (s *FileStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *FileStore) Load(key string) (string, time.Time, bool) {
+	p := s.path(key)
+	fi, err := os.Stat(p)
+	if err != nil || (runtime.GOOS != "windows" && fi.Mode().Perm()&0o077 != 0) {
+		return "", time.Time{}, false
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.AccessToken == "" {
+		_ = s.Delete(key)
+		return "", time.Time{}, false
+	}
+	// Tokens without a known expiry are not shared across invocations.
+	if e.RefreshAt.IsZero() || !s.now().Before(e.RefreshAt) {
+		_ = s.Delete(key)
+		return "", time.Time{}, false
+	}
+	return e.AccessToken, e.RefreshAt, true
+}
+
+func (s *FileStore) Save(key, token string, refreshAt time.Time) error {
+	if refreshAt.IsZero() {
+		return nil
+	}
+	data, err := json.Marshal(entry{AccessToken: token, RefreshAt: refreshAt})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, ".token-*")
+	if err != nil {
+		return fmt.Errorf("save token: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if err := tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("save token: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("save token: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save token: %w", err)
+	}
+	// rename is atomic, so concurrent invocations never read a partial entry
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		return fmt.Errorf("save token: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) Delete(key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete token: %w", err)
+	}
+	return nil
+}
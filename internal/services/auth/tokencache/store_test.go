@@ -0,0 +1,50 @@
+package tokencache
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tokens")
+	s, err := NewFileStore(dir)
+	require.NoError(t, err)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	_, _, ok := s.Load("a")
+	require.False(t, ok)
+
+	refreshAt := now.Add(time.Minute)
+	require.NoError(t, s.Save("a", "token-a", refreshAt))
+	require.NoError(t, s.Save("b", "token-b", time.Time{}), "tokens without expiry are not stored")
+
+	tok, at, ok := s.Load("a")
+	require.True(t, ok)
+	require.Equal(t, "token-a", tok)
+	require.True(t, refreshAt.Equal(at))
+	_, _, ok = s.Load("b")
+	require.False(t, ok)
+
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(s.path("a"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+		require.NoError(t, os.Chmod(s.path("a"), 0o644))
+		_, _, ok = s.Load("a")
+		require.False(t, ok, "entries readable by others are ignored")
+		require.NoError(t, os.Chmod(s.path("a"), 0o600))
+	}
+
+	now = refreshAt
+	_, _, ok = s.Load("a")
+	require.False(t, ok, "expired entries are ignored")
+	require.NoFileExists(t, s.path("a"))
+	require.NoError(t, s.Delete("a"))
+}
@@ -0,0 +1,59 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
+)
+
+// WaitForProcessInstanceState polls get until the instance reaches the desired state, shared by
+// the process instance services of all versions.
+// - Respects ctx cancellation/deadline; augments with cfg.Timeout if set
+// - Returns nil on success or an error on failure/timeout.
+func WaitForProcessInstanceState(ctx context.Context, cfg BackoffConfig, log *slog.Logger, key int64, desiredState processinstance.State,
+	get func(ctx context.Context, key int64) (processinstance.ProcessInstance, error)) error {
+	if cfg.Timeout > 0 {
+		deadline := time.Now().Add(cfg.Timeout)
+		if dl, ok := ctx.Deadline(); !ok || deadline.Before(dl) {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+		}
+	}
+
+	attempts := 0
+	delay := cfg.InitialDelay
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		attempts++
+
+		pi, err := get(ctx, key)
+		switch {
+		case err == nil && pi.State.EqualsIgnoreCase(desiredState):
+			log.Debug(fmt.Sprintf("process instance %d reached desired state %q", key, desiredState))
+			return nil
+		case err == nil:
+			log.Debug(fmt.Sprintf("process instance %d currently in state %q; waiting...", key, pi.State))
+		case errors.Is(err, processinstance.ErrNotFound):
+			log.Debug(fmt.Sprintf("process instance %d is absent (not found); waiting...", key))
+		default:
+			log.Error(fmt.Sprintf("fetching state for %d failed: %v (will retry)", key, err))
+		}
+		if cfg.MaxRetries > 0 && attempts >= cfg.MaxRetries {
+			return fmt.Errorf("exceeded max_retries (%d) waiting for state %q", cfg.MaxRetries, desiredState)
+		}
+		select {
+		case <-time.After(delay):
+			delay = cfg.NextDelay(delay)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
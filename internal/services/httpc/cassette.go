@@ -0,0 +1,288 @@
+package httpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrNoRecording = errors.New("replay: no recorded response")
+
+const redacted = "REDACTED"
+
+// Secrets are redacted before an interaction is written, so cassettes can be committed.
+var (
+	redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Csrf-Token", "X-Xsrf-Token"}
+	redactedParams  = []string{"password", "client_secret", "client_assertion", "refresh_token"}
+	redactedFields  = regexp.MustCompile(`"(access_token|refresh_token|id_token|password|client_secret)"\s*:\s*"[^"]*"`)
+)
+
+// interaction is a request/response pair as stored in a cassette file.
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// matchKey identifies the requests a recorded response is served for: method, path, sorted query and,
+// for JSON requests, the body. Other bodies, like token requests with a fresh assertion each time, are ignored.
+func (r recordedRequest) matchKey() string {
+	key := r.Method + " "
+	if u, err := url.Parse(r.URL); err == nil {
+		key += u.Path
+		if q := u.Query().Encode(); q != "" {
+			key += "?" + q
+		}
+	} else {
+		key += r.URL
+	}
+	if r.Body != "" && strings.Contains(r.Header.Get("Content-Type"), "json") {
+		var buf bytes.Buffer
+		if json.Compact(&buf, []byte(r.Body)) == nil {
+			key += " " + buf.String()
+		} else {
+			key += " " + r.Body
+		}
+	}
+	return key
+}
+
+// recordSeq numbers the files of all record transports, the API and the token client record to the same dir.
+var recordSeq atomic.Int64
+
+// recordTransport writes every request/response pair, redacted, as a file to dir.
+type recordTransport struct {
+	base http.RoundTripper
+	dir  string
+}
+
+func newRecordTransport(base http.RoundTripper, dir string) (*recordTransport, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("record: %w", err)
+	}
+	return &recordTransport{base: base, dir: dir}, nil
+}
+
+func (t *recordTransport) rt() http.RoundTripper {
+	if t.base != nil {
+		return t.base
+	}
+	return http.DefaultTransport
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec, err := newRecordedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.rt().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := redactHeader(resp.Header)
+	header.Del("Content-Length") // the redacted body may be shorter
+	header.Del("Date")
+	in := interaction{Request: rec, Response: recordedResponse{
+		Status: resp.StatusCode,
+		Header: header,
+		Body:   redactBody(string(body)),
+	}}
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%04d-%s-%s.json", recordSeq.Add(1), strings.ToLower(req.Method), slug(req.URL.Path))
+	if err = os.WriteFile(filepath.Join(t.dir, name), append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("record: %w", err)
+	}
+	return resp, nil
+}
+
+func newRecordedRequest(req *http.Request) (recordedRequest, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		rc := req.Body
+		if req.GetBody != nil {
+			var err error
+			if rc, err = req.GetBody(); err != nil {
+				return recordedRequest{}, err
+			}
+		}
+		var err error
+		body, err = io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			return recordedRequest{}, err
+		}
+		if req.GetBody == nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+	}
+	u := *req.URL
+	u.RawQuery = redactParams(u.Query()).Encode()
+	b := string(body)
+	if strings.Contains(req.Header.Get("Content-Type"), "x-www-form-urlencoded") {
+		if form, err := url.ParseQuery(b); err == nil {
+			b = redactParams(form).Encode()
+		}
+	}
+	return recordedRequest{Method: req.Method, URL: u.String(), Header: redactHeader(req.Header), Body: redactBody(b)}, nil
+}
+
+// replayTransport serves the responses of a cassette dir instead of sending requests. Identical requests
+// get the recorded responses in recording order; once they are used up, the last one is repeated.
+type replayTransport struct {
+	mu    sync.Mutex
+	queue map[string][]recordedResponse
+}
+
+func newReplayTransport(dir string) (*replayTransport, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("replay: no recordings in %s", dir)
+	}
+	sort.Strings(files)
+	t := &replayTransport{queue: make(map[string][]recordedResponse)}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("replay: %w", err)
+		}
+		var in interaction
+		if err = json.Unmarshal(data, &in); err != nil {
+			return nil, fmt.Errorf("replay: %s: %w", f, err)
+		}
+		key := in.Request.matchKey()
+		t.queue[key] = append(t.queue[key], in.Response)
+	}
+	return t, nil
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec, err := newRecordedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	key := rec.matchKey()
+	t.mu.Lock()
+	q := t.queue[key]
+	if len(q) == 0 {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("%w for %s", ErrNoRecording, key)
+	}
+	r := q[0]
+	if len(q) > 1 {
+		t.queue[key] = q[1:]
+	}
+	t.mu.Unlock()
+
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}, nil
+}
+
+func redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range redactedHeaders {
+		vs := out.Values(name)
+		for i, v := range vs {
+			vs[i] = redactHeaderValue(name, v)
+		}
+	}
+	return out
+}
+
+// redactHeaderValue keeps cookie names and attributes, cookie auth checks that a session cookie was set.
+// Expiry is dropped, so that a replayed cookie never has expired.
+func redactHeaderValue(name, v string) string {
+	switch name {
+	case "Set-Cookie":
+		c, err := http.ParseSetCookie(v)
+		if err != nil {
+			return redacted
+		}
+		c.Value, c.Expires, c.RawExpires, c.MaxAge = redacted, time.Time{}, "", 0
+		return c.String()
+	case "Cookie":
+		cookies, err := http.ParseCookie(v)
+		if err != nil {
+			return redacted
+		}
+		parts := make([]string, len(cookies))
+		for i, c := range cookies {
+			parts[i] = c.Name + "=" + redacted
+		}
+		return strings.Join(parts, "; ")
+	default:
+		return redacted
+	}
+}
+
+func redactParams(v url.Values) url.Values {
+	for _, p := range redactedParams {
+		if v.Has(p) {
+			v.Set(p, redacted)
+		}
+	}
+	return v
+}
+
+func redactBody(b string) string {
+	return redactedFields.ReplaceAllString(b, `"$1":"`+redacted+`"`)
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+func slug(path string) string {
+	s := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(path), "-"), "-")
+	if s == "" {
+		return "root"
+	}
+	return s[:min(len(s), 60)]
+}
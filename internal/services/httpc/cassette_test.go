@@ -0,0 +1,79 @@
+package httpc
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/token":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"access_token":"secret-token","expires_in":300}`)
+		default:
+			http.SetCookie(w, &http.Cookie{Name: "OPERATE-SESSION", Value: "secret-session", MaxAge: 60})
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"items":[{"key":`+r.URL.Query().Get("n")+`}]}`)
+		}
+	}))
+	defer ts.Close()
+	dir := t.TempDir()
+
+	rec, err := newRecordTransport(http.DefaultTransport, dir)
+	require.NoError(t, err)
+	client := &http.Client{Transport: rec}
+	resp, err := client.PostForm(ts.URL+"/token", url.Values{"client_id": {"camunder"}, "client_secret": {"s3cret"}})
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	for _, n := range []string{"1", "2"} {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/v2/search?n="+n, strings.NewReader(`{ "page": 1 }`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret-token")
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		require.JSONEq(t, `{"items":[{"key":`+n+`}]}`, string(body), "the response is passed on unchanged")
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		require.NoError(t, err)
+		for _, secret := range []string{"secret-token", "secret-session", "s3cret"} {
+			require.NotContains(t, string(data), secret, "%s is redacted", filepath.Base(f))
+		}
+	}
+
+	replay, err := newReplayTransport(dir)
+	require.NoError(t, err)
+	client = &http.Client{Transport: replay}
+	for _, n := range []string{"2", "1"} {
+		req, _ := http.NewRequest(http.MethodPost, "http://elsewhere/v2/search?n="+n, strings.NewReader(`{"page":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.JSONEq(t, `{"items":[{"key":`+n+`}]}`, string(body))
+		require.Len(t, resp.Cookies(), 1, "session cookies are replayed with a redacted value")
+	}
+	require.Equal(t, 3, calls, "replay sends no requests")
+
+	_, err = client.Get("http://elsewhere/v2/other")
+	require.ErrorIs(t, err, ErrNoRecording)
+}
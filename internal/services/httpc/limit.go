@@ -0,0 +1,152 @@
+package httpc
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafvonb/camunder/internal/config"
+)
+
+// limitTransport keeps the request rate and the requests in flight of each API within the configured
+// limits, so that bulk operations and walks do not flood the cluster.
+type limitTransport struct {
+	base http.RoundTripper
+	cfg  *config.Config // read per request, the authenticators fill in missing base URLs later
+	now  func() time.Time
+
+	mu     sync.Mutex
+	limits map[string]*apiLimit // by API key, "" for requests to other hosts
+}
+
+type apiLimit struct {
+	bucket *tokenBucket  // nil for no rate limit
+	slots  chan struct{} // nil for no concurrency limit
+}
+
+func newLimitTransport(base http.RoundTripper, cfg *config.Config) *limitTransport {
+	return &limitTransport{base: base, cfg: cfg, now: time.Now, limits: make(map[string]*apiLimit)}
+}
+
+func (t *limitTransport) rt() http.RoundTripper {
+	if t.base != nil {
+		return t.base
+	}
+	return http.DefaultTransport
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	l := t.limit(apiKeyOf(t.cfg.APIs, req.URL))
+	ctx := req.Context()
+
+	if l.bucket != nil {
+		if wait := l.bucket.reserve(); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				l.bucket.cancel()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+	if l.slots == nil {
+		return t.rt().RoundTrip(req)
+	}
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := sync.OnceFunc(func() { <-l.slots })
+	resp, err := t.rt().RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// the request is in flight until its response has been read
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+func (t *limitTransport) limit(key string) *apiLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if l, ok := t.limits[key]; ok {
+		return l
+	}
+	l := &apiLimit{}
+	if h := t.cfg.HTTP; h.RateLimit > 0 {
+		l.bucket = newTokenBucket(h.RateLimit, max(h.Burst, 1), t.now)
+	}
+	if n := t.cfg.HTTP.MaxConcurrent; n > 0 {
+		l.slots = make(chan struct{}, n)
+	}
+	t.limits[key] = l
+	return l
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+// apiKeyOf returns the key of the API whose base URL is the longest prefix of u, or "" if none is.
+func apiKeyOf(apis config.APIs, u *url.URL) string {
+	key, longest := "", -1
+	for _, api := range []config.API{apis.Camunda, apis.Operate, apis.Tasklist} {
+		base, err := url.Parse(api.BaseURL)
+		if err != nil || api.BaseURL == "" || !strings.EqualFold(base.Host, u.Host) {
+			continue
+		}
+		p := strings.TrimRight(base.Path, "/")
+		if (u.Path == p || strings.HasPrefix(u.Path, p+"/")) && len(p) > longest {
+			key, longest = api.Key, len(p)
+		}
+	}
+	return key
+}
+
+// tokenBucket allows rate requests per second on average and up to burst requests at once.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now func() time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: now(), now: now}
+}
+
+// reserve takes a token and returns how long to wait until it is available.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that was not used.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	b.tokens = min(b.burst, b.tokens+1)
+	b.mu.Unlock()
+}
@@ -0,0 +1,87 @@
+package httpc
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafvonb/camunder/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newTokenBucket(10, 2, func() time.Time { return now })
+
+	require.Zero(t, b.reserve())
+	require.Zero(t, b.reserve())
+	require.Equal(t, 100*time.Millisecond, b.reserve(), "burst used up")
+	b.cancel()
+
+	now = now.Add(time.Second)
+	require.Zero(t, b.reserve())
+	require.Zero(t, b.reserve())
+	require.Equal(t, 100*time.Millisecond, b.reserve(), "refilled up to the burst only")
+}
+
+func TestAPIKeyOf(t *testing.T) {
+	apis := config.APIs{
+		Camunda:  config.API{Key: config.CamundaApiKeyConst, BaseURL: "http://localhost:8080/v2"},
+		Operate:  config.API{Key: config.OperateApiKeyConst, BaseURL: "http://localhost:8080"},
+		Tasklist: config.API{Key: config.TasklistApiKeyConst, BaseURL: "http://tasklist:8082/"},
+	}
+	for raw, want := range map[string]string{
+		"http://localhost:8080/v2/process-instances/search": config.CamundaApiKeyConst,
+		"http://localhost:8080/v1/process-instances/search": config.OperateApiKeyConst,
+		"http://localhost:8080/v2x":                         config.OperateApiKeyConst,
+		"http://tasklist:8082/v1/tasks":                     config.TasklistApiKeyConst,
+		"http://keycloak:18080/token":                       "",
+	} {
+		u, _ := url.Parse(raw)
+		require.Equal(t, want, apiKeyOf(apis, u), raw)
+	}
+}
+
+func TestLimit_MaxConcurrentPerAPI(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		inFlight.Add(-1)
+	}))
+	defer ts.Close()
+
+	cfg := &config.Config{
+		HTTP: config.HTTP{Timeout: "5s", MaxConcurrent: 2},
+		APIs: config.APIs{Camunda: config.API{Key: config.CamundaApiKeyConst, BaseURL: ts.URL + "/v2"}},
+	}
+	svc, err := New(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := svc.Client().Get(ts.URL + "/v2/topology")
+			if err == nil {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(2), peak.Load())
+}
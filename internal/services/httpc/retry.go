@@ -0,0 +1,142 @@
+package httpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/grafvonb/camunder/internal/config"
+)
+
+// retryStatusCodes are the responses worth another attempt, typically sent by a gateway or ingress.
+var retryStatusCodes = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// retryTransport resends requests that failed with a transient error, waiting according to the
+// backoff settings or the Retry-After header of the response.
+type retryTransport struct {
+	base http.RoundTripper
+	cfg  config.HTTPRetry
+	log  *slog.Logger
+	now  func() time.Time
+}
+
+func (t *retryTransport) rt() http.RoundTripper {
+	if t.base != nil {
+		return t.base
+	}
+	return http.DefaultTransport
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.retryable(req) {
+		return t.rt().RoundTrip(req)
+	}
+	start := t.now()
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+		resp, err := t.rt().RoundTrip(r)
+		if !transient(resp, err) || attempt > t.cfg.MaxRetries {
+			return resp, err
+		}
+
+		if delay == 0 {
+			delay = t.cfg.InitialDelay
+		} else {
+			delay = t.cfg.NextDelay(delay)
+		}
+		wait := delay
+		if after, ok := retryAfter(resp, t.now()); ok {
+			wait = after
+		}
+		if t.cfg.Timeout > 0 && t.now().Add(wait).Sub(start) > t.cfg.Timeout {
+			return resp, err
+		}
+		if t.log != nil {
+			t.log.Debug(fmt.Sprintf("retrying %s %s in %s (retry %d/%d): %s",
+				req.Method, req.URL.Redacted(), wait, attempt, t.cfg.MaxRetries, reason(resp, err)))
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether the request may be sent again: idempotent methods and searches by default,
+// and only if its body can be replayed.
+func (t *retryTransport) retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if t.cfg.NonIdempotent {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return strings.HasSuffix(strings.TrimRight(req.URL.Path, "/"), "/search")
+	}
+	return false
+}
+
+func transient(resp *http.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+			errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+	}
+	return retryStatusCodes[resp.StatusCode]
+}
+
+func reason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+// retryAfter parses the Retry-After header, given in seconds or as HTTP date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
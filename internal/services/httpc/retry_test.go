@@ -0,0 +1,73 @@
+package httpc
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafvonb/camunder/internal/config"
+	"github.com/grafvonb/camunder/internal/services/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry_TransientResponses(t *testing.T) {
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, r.Method+" "+r.URL.Path+" "+string(b))
+		if len(bodies)%3 != 0 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	cfg := &config.Config{HTTP: config.HTTP{Timeout: "5s", Retry: config.HTTPRetry{BackoffConfig: common.BackoffConfig{
+		Strategy:     common.BackoffExponential,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     5 * time.Millisecond,
+		MaxRetries:   3,
+		Multiplier:   2,
+		Timeout:      time.Second,
+	}}}}
+	svc, err := New(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	resp, err := svc.Client().Post(ts.URL+"/v2/process-instances/search", "application/json", strings.NewReader(`{"page":1}`))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, []string{
+		`POST /v2/process-instances/search {"page":1}`,
+		`POST /v2/process-instances/search {"page":1}`,
+		`POST /v2/process-instances/search {"page":1}`,
+	}, bodies)
+
+	bodies = nil
+	resp, err = svc.Client().Post(ts.URL+"/v2/process-instances/1/cancellation", "application/json", http.NoBody)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Len(t, bodies, 1, "non-idempotent requests are not retried by default")
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	resp := func(v string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": []string{v}}}
+	}
+	d, ok := retryAfter(resp("7"), now)
+	require.True(t, ok)
+	require.Equal(t, 7*time.Second, d)
+	d, ok = retryAfter(resp(now.Add(3*time.Second).Format(http.TimeFormat)), now)
+	require.True(t, ok)
+	require.Equal(t, 3*time.Second, d)
+	_, ok = retryAfter(resp("soon"), now)
+	require.False(t, ok)
+}
@@ -1,9 +1,11 @@
 package httpc
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/cookiejar"
@@ -17,14 +19,21 @@ import (
 var (
 	ErrNoHttpServiceInContext  = errors.New("no http service in context")
 	ErrInvalidServiceInContext = errors.New("invalid http service in context")
+	ErrDryRun                  = errors.New("dry-run: mutating request not sent")
 )
 
-var _ http.RoundTripper = (*authTransport)(nil)
+var (
+	_ http.RoundTripper = (*authTransport)(nil)
+	_ http.RoundTripper = (*dryRunTransport)(nil)
+	_ http.RoundTripper = (*retryTransport)(nil)
+	_ http.RoundTripper = (*limitTransport)(nil)
+)
 
 type Service struct {
-	c   *http.Client
-	cfg *config.Config
-	log *slog.Logger
+	c      *http.Client
+	cfg    *config.Config
+	log    *slog.Logger
+	dryRun bool
 }
 
 type Option func(*Service)
@@ -49,11 +58,25 @@ func WithCookieJar() Option {
 	return func(s *Service) { _ = s.InstallCookieJar() }
 }
 
+// WithDryRun Reject all mutating requests; only reads, searches and logins reach the server
+func WithDryRun(enabled bool) Option {
+	return func(s *Service) {
+		if enabled {
+			s.InstallDryRun()
+		}
+	}
+}
+
 // WithAuthEditor Install an auth editor transport now
 func WithAuthEditor(ed authcore.RequestEditor) Option {
 	return func(s *Service) { s.InstallAuthEditor(ed) }
 }
 
+// WithAuthenticator Install the authenticator's editor transport now, with a token refresh on 401
+func WithAuthenticator(a authcore.Authenticator) Option {
+	return func(s *Service) { s.InstallAuthenticator(a) }
+}
+
 func New(cfg *config.Config, log *slog.Logger, opts ...Option) (*Service, error) {
 	if cfg == nil {
 		return nil, errors.New("cfg is nil")
@@ -62,7 +85,18 @@ func New(cfg *config.Config, log *slog.Logger, opts ...Option) (*Service, error)
 	if err != nil {
 		return nil, err
 	}
-	httpClient := &http.Client{Timeout: d}
+	transport, err := NewTransport(cfg.HTTP)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.HTTP.RateLimit > 0 || cfg.HTTP.MaxConcurrent > 0 {
+		transport = newLimitTransport(transport, cfg)
+	}
+	// retries below the limits would bypass them, so each attempt passes the limits again
+	if cfg.HTTP.Retry.Enabled() {
+		transport = &retryTransport{base: transport, cfg: cfg.HTTP.Retry, log: log, now: time.Now}
+	}
+	httpClient := &http.Client{Timeout: d, Transport: transport}
 	s := &Service{c: httpClient, cfg: cfg, log: log}
 	for _, opt := range opts {
 		opt(s)
@@ -72,6 +106,8 @@ func New(cfg *config.Config, log *slog.Logger, opts ...Option) (*Service, error)
 
 func (s *Service) Client() *http.Client { return s.c }
 
+func (s *Service) DryRun() bool { return s.dryRun }
+
 func (s *Service) UseClient(c *http.Client) { s.c = c }
 
 func (s *Service) InstallCookieJar() error {
@@ -90,11 +126,44 @@ func (s *Service) InstallAuthEditor(ed authcore.RequestEditor) {
 	s.c.Transport = &authTransport{base: s.c.Transport, editor: ed}
 }
 
+// InstallAuthenticator installs the authenticator's editor like InstallAuthEditor. In addition, a request
+// answered with 401 Unauthorized is sent once more after the authenticator's cache has been cleared and
+// it has been initialised again, e.g. because the cached token expired in the meantime.
+func (s *Service) InstallAuthenticator(a authcore.Authenticator) {
+	s.c.Transport = &authTransport{
+		base:   s.c.Transport,
+		editor: a.Editor(),
+		renew: func(ctx context.Context) error {
+			a.ClearCache()
+			return a.Init(ctx)
+		},
+		jar: s.c.Jar,
+		log: s.log,
+	}
+}
+
+// InstallDryRun installs the dry-run guard. Call it before InstallAuthEditor, so that the guard
+// is the innermost transport and sees the requests as they would be sent.
+func (s *Service) InstallDryRun() {
+	if s.dryRun {
+		return
+	}
+	s.dryRun = true
+	s.c.Transport = &dryRunTransport{base: s.c.Transport, log: s.log}
+}
+
 type authTransport struct {
 	base   http.RoundTripper
 	editor authcore.RequestEditor
+	renew  func(ctx context.Context) error // nil disables the replay on 401
+	jar    http.CookieJar                  // re-read on replay, as the client added the cookies before
+	log    *slog.Logger
 }
 
+// noRenewKey marks requests that must not trigger another renewal: replayed requests and those sent
+// while renewing (e.g. a cookie login that is itself answered with 401).
+type noRenewKey struct{}
+
 func (t *authTransport) rt() http.RoundTripper {
 	if t.base != nil {
 		return t.base
@@ -103,6 +172,51 @@ func (t *authTransport) rt() http.RoundTripper {
 }
 
 func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !t.canReplay(req) {
+		return resp, err
+	}
+
+	ctx := context.WithValue(req.Context(), noRenewKey{}, true)
+	retry := req.Clone(ctx)
+	if req.Body != nil && req.Body != http.NoBody {
+		reqBody, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = reqBody
+	}
+	if t.log != nil {
+		t.log.Debug(fmt.Sprintf("got 401 for %s %s, renewing credentials and retrying once", req.Method, req.URL.Redacted()))
+	}
+	// buffer and close the 401 before renewing: its body holds a slot of the concurrency limit,
+	// which the login requests of the renewal may need
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err := t.renew(ctx); err != nil {
+		if t.log != nil {
+			t.log.Debug(fmt.Sprintf("renewing credentials failed: %v", err))
+		}
+		return resp, nil
+	}
+	if t.jar != nil {
+		retry.Header.Del("Cookie")
+		for _, c := range t.jar.Cookies(retry.URL) {
+			retry.AddCookie(c)
+		}
+	}
+	return t.send(retry)
+}
+
+func (t *authTransport) canReplay(req *http.Request) bool {
+	if t.renew == nil || req.Context().Value(noRenewKey{}) != nil {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func (t *authTransport) send(req *http.Request) (*http.Response, error) {
 	if t.editor != nil {
 		if err := t.editor(req.Context(), req); err != nil {
 			return nil, withHints(err)
@@ -115,6 +229,49 @@ func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return resp, nil
 }
 
+// dryRunSafePathSuffixes are the POST endpoints that do not change any state.
+var dryRunSafePathSuffixes = []string{
+	"/search",    // Operate and Camunda search endpoints
+	"/api/login", // cookie authentication
+}
+
+type dryRunTransport struct {
+	base http.RoundTripper
+	log  *slog.Logger
+}
+
+func (t *dryRunTransport) rt() http.RoundTripper {
+	if t.base != nil {
+		return t.base
+	}
+	return http.DefaultTransport
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isSafeRequest(req) {
+		if t.log != nil {
+			t.log.Debug(fmt.Sprintf("dry-run: blocked %s %s", req.Method, req.URL.Redacted()))
+		}
+		return nil, fmt.Errorf("%w: %s %s", ErrDryRun, req.Method, req.URL.Path)
+	}
+	return t.rt().RoundTrip(req)
+}
+
+func isSafeRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		p := strings.TrimRight(req.URL.Path, "/")
+		for _, suffix := range dryRunSafePathSuffixes {
+			if strings.HasSuffix(p, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type ctxKey struct{}
 
 func (s *Service) ToContext(ctx context.Context) context.Context {
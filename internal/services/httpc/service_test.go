@@ -0,0 +1,159 @@
+package httpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grafvonb/camunder/internal/config"
+	authcore "github.com/grafvonb/camunder/internal/services/auth/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun_BlocksMutatingRequests(t *testing.T) {
+	var hits []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits = append(hits, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	cfg := &config.Config{HTTP: config.HTTP{Timeout: "5s"}}
+	svc, err := New(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), WithDryRun(true))
+	require.NoError(t, err)
+	require.True(t, svc.DryRun())
+
+	tests := []struct {
+		method  string
+		path    string
+		allowed bool
+	}{
+		{http.MethodGet, "/v1/process-instances/1", true},
+		{http.MethodPost, "/v1/process-instances/search", true},
+		{http.MethodPost, "/v2/process-instances/search/", true},
+		{http.MethodPost, "/api/login", true},
+		{http.MethodDelete, "/v1/process-instances/1", false},
+		{http.MethodPost, "/v2/process-instances/1/cancellation", false},
+		{http.MethodPatch, "/v2/element-instances/1/variables", false},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, ts.URL+tt.path, strings.NewReader("{}"))
+		resp, err := svc.Client().Do(req)
+		if tt.allowed {
+			require.NoError(t, err, "%s %s", tt.method, tt.path)
+			_ = resp.Body.Close()
+		} else {
+			require.ErrorIs(t, err, ErrDryRun, "%s %s", tt.method, tt.path)
+		}
+	}
+	require.Equal(t, []string{
+		"GET /v1/process-instances/1",
+		"POST /v1/process-instances/search",
+		"POST /v2/process-instances/search/",
+		"POST /api/login",
+	}, hits)
+}
+
+type stubAuthenticator struct {
+	token   string
+	cleared int
+}
+
+func (a *stubAuthenticator) Name() string                 { return "stub" }
+func (a *stubAuthenticator) Init(_ context.Context) error { return nil }
+func (a *stubAuthenticator) ClearCache()                  { a.cleared++; a.token = "fresh" }
+func (a *stubAuthenticator) Editor() authcore.RequestEditor {
+	return func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+		return nil
+	}
+}
+
+func TestAuthenticator_ReplaysOnceOn401(t *testing.T) {
+	var seen []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		seen = append(seen, r.Header.Get("Authorization")+" "+string(body))
+		if r.Header.Get("Authorization") != "Bearer fresh" || r.URL.Path == "/denied" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	a := &stubAuthenticator{token: "expired"}
+	cfg := &config.Config{HTTP: config.HTTP{Timeout: "5s"}}
+	svc, err := New(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), WithAuthenticator(a))
+	require.NoError(t, err)
+
+	resp, err := svc.Client().Post(ts.URL+"/search", "application/json", strings.NewReader(`{"a":1}`))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 1, a.cleared)
+	require.Equal(t, []string{`Bearer expired {"a":1}`, `Bearer fresh {"a":1}`}, seen)
+
+	seen = nil
+	resp, err = svc.Client().Get(ts.URL + "/denied")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.Equal(t, 2, a.cleared)
+	require.Len(t, seen, 2, "a request is replayed only once")
+}
+
+// loginAuthenticator logs in with a request through the client it authenticates, like the cookie login.
+type loginAuthenticator struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+func (a *loginAuthenticator) Name() string { return "login" }
+func (a *loginAuthenticator) Init(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url+"/api/login", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	a.token = "fresh"
+	return nil
+}
+func (a *loginAuthenticator) ClearCache() { a.token = "" }
+func (a *loginAuthenticator) Editor() authcore.RequestEditor {
+	return func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+		return nil
+	}
+}
+
+func TestAuthenticator_RenewsWithinConcurrencyLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/login" && r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, "expired")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	cfg := &config.Config{HTTP: config.HTTP{Timeout: "2s", MaxConcurrent: 1}}
+	svc, err := New(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	svc.InstallAuthenticator(&loginAuthenticator{client: svc.Client(), url: ts.URL, token: "expired"})
+
+	resp, err := svc.Client().Get(ts.URL + "/v2/topology")
+	require.NoError(t, err, "the login must not wait for the slot held by the 401")
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
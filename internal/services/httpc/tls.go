@@ -0,0 +1,65 @@
+package httpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/grafvonb/camunder/internal/config"
+)
+
+// NewTransport returns the base transport for the TLS settings, recording or replaying requests if
+// configured, or nil if Go's defaults apply. Clients that bypass the service's client, like the one
+// requesting OAuth2 tokens, use it too.
+func NewTransport(c config.HTTP) (http.RoundTripper, error) {
+	var transport http.RoundTripper
+	if c.TLS.IsSet() {
+		tc, err := tlsConfig(c.TLS)
+		if err != nil {
+			return nil, err
+		}
+		base, ok := http.DefaultTransport.(*http.Transport)
+		if !ok {
+			return nil, errors.New("default transport is not an *http.Transport")
+		}
+		t := base.Clone()
+		t.TLSClientConfig = tc
+		transport = t
+	}
+	switch {
+	case c.Replay != "":
+		return newReplayTransport(c.Replay)
+	case c.Record != "":
+		return newRecordTransport(transport, c.Record)
+	}
+	return transport, nil
+}
+
+func tlsConfig(c config.HTTPTLS) (*tls.Config, error) {
+	tc := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: c.InsecureSkipVerify} //nolint:gosec // opt-in
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", c.CAFile)
+		}
+		tc.RootCAs = pool
+	}
+	return tc, nil
+}
@@ -0,0 +1,211 @@
+package v87
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/grafvonb/camunder/internal/api/convert"
+	camundav87 "github.com/grafvonb/camunder/internal/api/gen/clients/camunda/camunda/v87"
+	operatev87 "github.com/grafvonb/camunder/internal/api/gen/clients/camunda/operate/v87"
+	"github.com/grafvonb/camunder/internal/config"
+	"github.com/grafvonb/camunder/pkg/camunda"
+	"github.com/grafvonb/camunder/pkg/camunda/incident"
+)
+
+const (
+	jsonContentType = "application/json"
+	// maxProcessDefinitions caps the versions looked up for a BPMN process ID filter.
+	maxProcessDefinitions int32 = 1000
+)
+
+type Service struct {
+	cc  *camundav87.ClientWithResponses
+	oc  *operatev87.ClientWithResponses
+	cfg *config.Config
+	log *slog.Logger
+}
+
+type Option func(*Service)
+
+func New(cfg *config.Config, httpClient *http.Client, log *slog.Logger, opts ...Option) (*Service, error) {
+	cc, err := camundav87.NewClientWithResponses(
+		cfg.APIs.Camunda.BaseURL,
+		camundav87.WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		return nil, err
+	}
+	co, err := operatev87.NewClientWithResponses(
+		cfg.APIs.Operate.BaseURL,
+		operatev87.WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{oc: co, cc: cc, cfg: cfg, log: log}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+func (s *Service) Capabilities(ctx context.Context) camunda.Capabilities {
+	return camunda.Capabilities{
+		APIVersion: camunda.V87,
+	}
+}
+
+func (s *Service) GetIncidentByKey(ctx context.Context, key int64) (incident.Incident, error) {
+	resp, err := s.oc.GetIncidentByKeyWithResponse(ctx, key)
+	if err != nil {
+		return incident.Incident{}, err
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return incident.Incident{}, fmt.Errorf("incident with key %d: %w", key, incident.ErrNotFound)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return incident.Incident{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return resp.JSON200.ToStable(), nil
+}
+
+func (s *Service) SearchIncidents(ctx context.Context, filter incident.SearchFilterOpts, size int32) (incident.Incidents, error) {
+	page, err := s.SearchIncidentsPage(ctx, filter, size, "")
+	if err != nil {
+		return incident.Incidents{}, err
+	}
+	return incident.Incidents{Total: int32(page.Total), Items: page.Items}, nil
+}
+
+// SearchIncidentsPage searches Operate for incidents. Operate cannot filter incidents by BPMN process ID,
+// so for that filter the keys of all versions of the process definition are looked up first and searched
+// one after the other; the cursor then records the definition being searched as well.
+func (s *Service) SearchIncidentsPage(ctx context.Context, filter incident.SearchFilterOpts, size int32, after camunda.Cursor) (camunda.Page[incident.Incident], error) {
+	if filter.BpmnProcessId == "" {
+		return s.searchPage(ctx, s.searchFilter(filter, 0), size, after)
+	}
+	keys, err := s.processDefinitionKeys(ctx, filter.BpmnProcessId)
+	if err != nil {
+		return camunda.Page[incident.Incident]{}, fmt.Errorf("looking up process definitions of %q: %w", filter.BpmnProcessId, err)
+	}
+	var c definitionCursor
+	if after != "" {
+		if err = json.Unmarshal([]byte(after), &c); err != nil {
+			return camunda.Page[incident.Incident]{}, fmt.Errorf("invalid cursor: %w", err)
+		}
+	}
+	var out camunda.Page[incident.Incident]
+	for c.Definition < len(keys) && int32(len(out.Items)) < size {
+		want := size - int32(len(out.Items))
+		page, err := s.searchPage(ctx, s.searchFilter(filter, keys[c.Definition]), want, c.After)
+		if err != nil {
+			return camunda.Page[incident.Incident]{}, err
+		}
+		if c.After == "" {
+			out.Total += page.Total
+		}
+		out.Items = append(out.Items, page.Items...)
+		if page.Next == "" || int32(len(page.Items)) < want {
+			c = definitionCursor{Definition: c.Definition + 1}
+		} else {
+			c.After = page.Next
+		}
+	}
+	if c.Definition < len(keys) {
+		b, err := json.Marshal(c)
+		if err != nil {
+			return camunda.Page[incident.Incident]{}, err
+		}
+		out.Next = camunda.Cursor(b)
+	}
+	return out, nil
+}
+
+// definitionCursor is the search position when incidents of several process definitions are searched.
+type definitionCursor struct {
+	Definition int            `json:"definition"`
+	After      camunda.Cursor `json:"after,omitempty"`
+}
+
+func (s *Service) searchPage(ctx context.Context, filter *operatev87.Incident, size int32, after camunda.Cursor) (camunda.Page[incident.Incident], error) {
+	body := operatev87.QueryIncidentAfter{
+		Filter: filter,
+		Size:   &size,
+	}
+	if after != "" {
+		body.SearchAfter = json.RawMessage(after)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return camunda.Page[incident.Incident]{}, err
+	}
+	resp, err := s.oc.SearchIncidentsWithBodyWithResponse(ctx, jsonContentType, bytes.NewReader(b))
+	if err != nil {
+		return camunda.Page[incident.Incident]{}, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return camunda.Page[incident.Incident]{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	next, err := resp.JSON200.Cursor()
+	if err != nil {
+		return camunda.Page[incident.Incident]{}, fmt.Errorf("read sort values: %w", err)
+	}
+	return camunda.Page[incident.Incident]{
+		Total: convert.Deref(resp.JSON200.Total, 0),
+		Items: resp.JSON200.ToStable().Items,
+		Next:  camunda.Cursor(next),
+	}, nil
+}
+
+func (s *Service) searchFilter(filter incident.SearchFilterOpts, processDefinitionKey int64) *operatev87.Incident {
+	return &operatev87.Incident{
+		TenantId:             convert.PtrIf(s.cfg.App.Tenant, ""),
+		Key:                  convert.PtrIfNonZero(filter.Key),
+		ProcessInstanceKey:   convert.PtrIfNonZero(filter.ProcessInstanceKey),
+		ProcessDefinitionKey: convert.PtrIfNonZero(processDefinitionKey),
+		JobKey:               convert.PtrIfNonZero(filter.JobKey),
+		Type:                 (*operatev87.IncidentType)(convert.PtrIf(filter.ErrorType, "")),
+		State:                (*operatev87.IncidentState)(convert.PtrIf(filter.State.String(), "")),
+	}
+}
+
+func (s *Service) processDefinitionKeys(ctx context.Context, bpmnProcessId string) ([]int64, error) {
+	size := maxProcessDefinitions
+	resp, err := s.oc.SearchProcessDefinitionsWithResponse(ctx, operatev87.SearchProcessDefinitionsJSONRequestBody{
+		Filter: &operatev87.ProcessDefinition{
+			BpmnProcessId: &bpmnProcessId,
+			TenantId:      convert.PtrIf(s.cfg.App.Tenant, ""),
+		},
+		Size: &size,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	pds := resp.JSON200.ToStable()
+	keys := make([]int64, 0, len(pds.Items))
+	for _, pd := range pds.Items {
+		keys = append(keys, pd.Key)
+	}
+	return keys, nil
+}
+
+func (s *Service) ResolveIncident(ctx context.Context, key int64) (incident.ResolveResponse, error) {
+	s.log.Debug(fmt.Sprintf("trying to resolve incident with key %d...", key))
+	resp, err := s.cc.ResolveIncidentWithResponse(ctx, strconv.FormatInt(key, 10))
+	if err != nil {
+		return incident.ResolveResponse{}, err
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return incident.ResolveResponse{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	s.log.Info(fmt.Sprintf("incident with key %d was successfully resolved", key))
+	return resp.ToStable(), nil
+}
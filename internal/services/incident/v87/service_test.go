@@ -0,0 +1,83 @@
+package v87_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v87 "github.com/grafvonb/camunder/internal/services/incident/v87"
+	"github.com/grafvonb/camunder/internal/testx"
+	"github.com/grafvonb/camunder/pkg/camunda"
+	"github.com/grafvonb/camunder/pkg/camunda/incident"
+	"github.com/stretchr/testify/require"
+)
+
+// incidentsByDefinition are the incident keys of the two versions of the searched process.
+var incidentsByDefinition = map[int64][]int64{11: {1, 2}, 12: {3, 4}}
+
+func searchHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/process-definitions/search":
+			_, _ = io.WriteString(w, `{"items":[{"key":11,"bpmnProcessId":"order"},{"key":12,"bpmnProcessId":"order"}],"total":2}`)
+		case "/v1/incidents/search":
+			var body struct {
+				Filter      struct{ ProcessDefinitionKey int64 } `json:"filter"`
+				SearchAfter []int64                              `json:"searchAfter"`
+				Size        int                                  `json:"size"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			all := incidentsByDefinition[body.Filter.ProcessDefinitionKey]
+			keys := all
+			if len(body.SearchAfter) == 1 {
+				for i, k := range all {
+					if k == body.SearchAfter[0] {
+						keys = all[i+1:]
+					}
+				}
+			}
+			keys = keys[:min(body.Size, len(keys))]
+			items := make([]map[string]any, 0, len(keys))
+			for _, k := range keys {
+				items = append(items, map[string]any{"key": k, "processDefinitionKey": body.Filter.ProcessDefinitionKey, "state": "ACTIVE"})
+			}
+			res := map[string]any{"items": items, "total": len(all)}
+			if len(keys) > 0 {
+				res["sortValues"] = []int64{keys[len(keys)-1]}
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(res))
+		default:
+			http.Error(w, fmt.Sprintf("unexpected path %s", r.URL.Path), http.StatusNotFound)
+		}
+	}
+}
+
+func TestService_SearchIncidentsPage_ByBpmnProcessIdSpansDefinitions(t *testing.T) {
+	ts := httptest.NewServer(searchHandler(t))
+	t.Cleanup(ts.Close)
+	cfg := testx.TestConfig()
+	cfg.APIs.Camunda.BaseURL = ts.URL + "/v2"
+	cfg.APIs.Operate.BaseURL = ts.URL
+	svc, err := v87.New(cfg, ts.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	filter := incident.SearchFilterOpts{BpmnProcessId: "order"}
+	first, err := svc.SearchIncidentsPage(t.Context(), filter, 3, "")
+	require.NoError(t, err)
+	require.Equal(t, int64(4), first.Total)
+	require.Len(t, first.Items, 3)
+	require.NotEmpty(t, first.Next)
+
+	all, _, err := camunda.SearchAll(t.Context(), svc.SearchIncidentsPage, filter, 3, 0)
+	require.NoError(t, err)
+	keys := make([]int64, 0, len(all))
+	for _, it := range all {
+		keys = append(keys, it.Key)
+	}
+	require.Equal(t, []int64{1, 2, 3, 4}, keys)
+}
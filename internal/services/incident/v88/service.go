@@ -0,0 +1,146 @@
+package v88
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/grafvonb/camunder/internal/api/convert"
+	camundav88 "github.com/grafvonb/camunder/internal/api/gen/clients/camunda/camunda/v88"
+	"github.com/grafvonb/camunder/internal/config"
+	"github.com/grafvonb/camunder/pkg/camunda"
+	"github.com/grafvonb/camunder/pkg/camunda/incident"
+)
+
+const jsonContentType = "application/json"
+
+type Service struct {
+	cc  *camundav88.ClientWithResponses
+	cfg *config.Config
+	log *slog.Logger
+}
+
+type Option func(*Service)
+
+func New(cfg *config.Config, httpClient *http.Client, log *slog.Logger, opts ...Option) (*Service, error) {
+	cc, err := camundav88.NewClientWithResponses(
+		cfg.APIs.Camunda.BaseURL,
+		camundav88.WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{cc: cc, cfg: cfg, log: log}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+func (s *Service) Capabilities(ctx context.Context) camunda.Capabilities {
+	return camunda.Capabilities{
+		APIVersion: camunda.V88,
+	}
+}
+
+func (s *Service) GetIncidentByKey(ctx context.Context, key int64) (incident.Incident, error) {
+	resp, err := s.cc.GetIncidentWithResponse(ctx, camundav88.FormatKey(key))
+	if err != nil {
+		return incident.Incident{}, err
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return incident.Incident{}, fmt.Errorf("incident with key %d: %w", key, incident.ErrNotFound)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return incident.Incident{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return resp.JSON200.ToStable(), nil
+}
+
+func (s *Service) SearchIncidents(ctx context.Context, filter incident.SearchFilterOpts, size int32) (incident.Incidents, error) {
+	var page camundav88.SearchQueryPageRequest
+	if err := page.FromOffsetPagination(camundav88.OffsetPagination{Limit: &size}); err != nil {
+		return incident.Incidents{}, err
+	}
+	result, err := s.search(ctx, filter, page)
+	if err != nil {
+		return incident.Incidents{}, err
+	}
+	return result.ToStable(), nil
+}
+
+func (s *Service) SearchIncidentsPage(ctx context.Context, filter incident.SearchFilterOpts, size int32, after camunda.Cursor) (camunda.Page[incident.Incident], error) {
+	var page camundav88.SearchQueryPageRequest
+	var err error
+	if after == "" {
+		err = page.FromOffsetPagination(camundav88.OffsetPagination{Limit: &size})
+	} else {
+		err = page.FromCursorForwardPagination(camundav88.CursorForwardPagination{After: string(after), Limit: &size})
+	}
+	if err != nil {
+		return camunda.Page[incident.Incident]{}, err
+	}
+	result, err := s.search(ctx, filter, page)
+	if err != nil {
+		return camunda.Page[incident.Incident]{}, err
+	}
+	var next camunda.Cursor
+	if result.Page.EndCursor != nil && *result.Page.EndCursor != nil {
+		next = camunda.Cursor(fmt.Sprint(*result.Page.EndCursor))
+	}
+	return camunda.Page[incident.Incident]{
+		Total: int64(result.Page.TotalItems),
+		Items: result.ToStable().Items,
+		Next:  next,
+	}, nil
+}
+
+func (s *Service) search(ctx context.Context, filter incident.SearchFilterOpts, page camundav88.SearchQueryPageRequest) (*camundav88.IncidentQueryResult, error) {
+	f := camundav88.IncidentQueryFilter{
+		TenantId:            convert.PtrIf(s.cfg.App.Tenant, ""),
+		ProcessDefinitionId: convert.PtrIf(filter.BpmnProcessId, ""),
+		ErrorType:           convert.PtrIf(filter.ErrorType, ""),
+		State:               convert.PtrIf(filter.State.String(), ""),
+	}
+	if filter.Key > 0 {
+		f.IncidentKey = convert.Ptr(camundav88.FormatKey(filter.Key))
+	}
+	if filter.ProcessInstanceKey > 0 {
+		f.ProcessInstanceKey = convert.Ptr(camundav88.FormatKey(filter.ProcessInstanceKey))
+	}
+	if filter.JobKey > 0 {
+		f.JobKey = convert.Ptr(camundav88.FormatKey(filter.JobKey))
+	}
+	body, err := json.Marshal(camundav88.IncidentQuery{Filter: &f, Page: &page})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.cc.SearchIncidentsWithBodyWithResponse(ctx, jsonContentType, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	var result camundav88.IncidentQueryResult
+	if err = json.Unmarshal(resp.Body, &result); err != nil {
+		return nil, fmt.Errorf("decode search result: %w", err)
+	}
+	return &result, nil
+}
+
+func (s *Service) ResolveIncident(ctx context.Context, key int64) (incident.ResolveResponse, error) {
+	s.log.Debug(fmt.Sprintf("trying to resolve incident with key %d...", key))
+	resp, err := s.cc.ResolveIncidentWithResponse(ctx, camundav88.FormatKey(key), camundav88.ResolveIncidentJSONRequestBody{})
+	if err != nil {
+		return incident.ResolveResponse{}, err
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return incident.ResolveResponse{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	s.log.Info(fmt.Sprintf("incident with key %d was successfully resolved", key))
+	return resp.ToStable(), nil
+}
@@ -1,7 +1,9 @@
 package v87
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -54,12 +56,8 @@ func (s *Service) GetProcessDefinitionByKey(ctx context.Context, key int64) (pro
 
 func (s *Service) SearchProcessDefinitions(ctx context.Context, filter processdefinition.SearchFilterOpts, size int32) (processdefinition.ProcessDefinitions, error) {
 	body := operatev87.QueryProcessDefinition{
-		Filter: &operatev87.ProcessDefinition{
-			BpmnProcessId: &filter.BpmnProcessId,
-			Version:       convert.PtrIfNonZero(filter.Version),
-			VersionTag:    &filter.VersionTag,
-		},
-		Size: &size,
+		Filter: searchFilter(filter),
+		Size:   &size,
 	}
 	resp, err := s.c.SearchProcessDefinitionsWithResponse(ctx, body)
 	if err != nil {
@@ -70,3 +68,41 @@ func (s *Service) SearchProcessDefinitions(ctx context.Context, filter processde
 	}
 	return resp.JSON200.ToStable(), nil
 }
+
+func (s *Service) SearchProcessDefinitionsPage(ctx context.Context, filter processdefinition.SearchFilterOpts, size int32, after camunda.Cursor) (camunda.Page[processdefinition.ProcessDefinition], error) {
+	body := operatev87.QueryProcessDefinitionAfter{
+		Filter: searchFilter(filter),
+		Size:   &size,
+	}
+	if after != "" {
+		body.SearchAfter = json.RawMessage(after)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return camunda.Page[processdefinition.ProcessDefinition]{}, err
+	}
+	resp, err := s.c.SearchProcessDefinitionsWithBodyWithResponse(ctx, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return camunda.Page[processdefinition.ProcessDefinition]{}, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return camunda.Page[processdefinition.ProcessDefinition]{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	next, err := resp.JSON200.Cursor()
+	if err != nil {
+		return camunda.Page[processdefinition.ProcessDefinition]{}, fmt.Errorf("read sort values: %w", err)
+	}
+	return camunda.Page[processdefinition.ProcessDefinition]{
+		Total: convert.Deref(resp.JSON200.Total, 0),
+		Items: resp.JSON200.ToStable().Items,
+		Next:  camunda.Cursor(next),
+	}, nil
+}
+
+func searchFilter(filter processdefinition.SearchFilterOpts) *operatev87.ProcessDefinition {
+	return &operatev87.ProcessDefinition{
+		BpmnProcessId: &filter.BpmnProcessId,
+		Version:       convert.PtrIfNonZero(filter.Version),
+		VersionTag:    &filter.VersionTag,
+	}
+}
@@ -35,3 +35,7 @@ func (s *Service) GetProcessDefinitionByKey(ctx context.Context, key int64) (pro
 func (s *Service) SearchProcessDefinitions(ctx context.Context, filter processdefinition.SearchFilterOpts, size int32) (processdefinition.ProcessDefinitions, error) {
 	panic("not implemented for v88")
 }
+
+func (s *Service) SearchProcessDefinitionsPage(ctx context.Context, filter processdefinition.SearchFilterOpts, size int32, after camunda.Cursor) (camunda.Page[processdefinition.ProcessDefinition], error) {
+	return camunda.Page[processdefinition.ProcessDefinition]{}, camunda.ErrNotSupported
+}
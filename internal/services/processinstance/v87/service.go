@@ -1,12 +1,14 @@
 package v87
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/grafvonb/camunder/internal/api/convert"
 	camundav87 "github.com/grafvonb/camunder/internal/api/gen/clients/camunda/camunda/v87"
@@ -17,7 +19,14 @@ import (
 	"github.com/grafvonb/camunder/internal/config"
 )
 
-const wrongStateMessage400 = "Process instances needs to be in one of the states [COMPLETED, CANCELED]"
+const (
+	wrongStateMessage400 = "Process instances needs to be in one of the states [COMPLETED, CANCELED]"
+	jsonContentType      = "application/json"
+	// elementInstancesPageSize is the page size of the element instance searches.
+	elementInstancesPageSize int32 = 1000
+	// childrenPageSize is the page size of the children searches of a walk.
+	childrenPageSize int32 = 1000
+)
 
 type Service struct {
 	cc  *camundav87.ClientWithResponses
@@ -66,7 +75,7 @@ func (s *Service) FilterProcessInstanceWithOrphanParent(ctx context.Context, ite
 			continue
 		}
 		_, err := s.GetProcessInstanceByKey(ctx, it.ParentKey)
-		if err != nil && strings.Contains(err.Error(), "status 404") {
+		if errors.Is(err, processinstance.ErrNotFound) {
 			result = append(result, it)
 		} else if err != nil {
 			return nil, err
@@ -80,6 +89,9 @@ func (s *Service) GetProcessInstanceByKey(ctx context.Context, key int64) (proce
 	if err != nil {
 		return processinstance.ProcessInstance{}, err
 	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return processinstance.ProcessInstance{}, fmt.Errorf("process instance with key %d: %w", key, processinstance.ErrNotFound)
+	}
 	if resp.StatusCode() != http.StatusOK {
 		return processinstance.ProcessInstance{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
 	}
@@ -91,25 +103,17 @@ func (s *Service) GetDirectChildrenOfProcessInstance(ctx context.Context, key in
 	filter := processinstance.SearchFilterOpts{
 		ParentKey: key,
 	}
-	resp, err := s.SearchForProcessInstances(ctx, filter, 1000)
+	// wide call activities (e.g. multi-instance) may have more children than fit in one page
+	items, total, err := camunda.SearchAll(ctx, s.SearchForProcessInstancesPage, filter, childrenPageSize, 0)
 	if err != nil {
 		return processinstance.ProcessInstances{}, fmt.Errorf("searching for children of process instance with key %d: %w", key, err)
 	}
-	return resp, nil
+	return processinstance.ProcessInstances{Total: int32(total), Items: items}, nil
 }
 
 func (s *Service) SearchForProcessInstances(ctx context.Context, filter processinstance.SearchFilterOpts, size int32) (processinstance.ProcessInstances, error) {
-	st := StateOrNil(filter.State)
-	f := operatev87.ProcessInstance{
-		TenantId:          &s.cfg.App.Tenant,
-		BpmnProcessId:     &filter.BpmnProcessId,
-		ProcessVersion:    convert.PtrIfNonZero(filter.ProcessVersion),
-		ProcessVersionTag: &filter.ProcessVersionTag,
-		State:             st,
-		ParentKey:         convert.PtrIfNonZero(filter.ParentKey),
-	}
 	body := operatev87.SearchProcessInstancesJSONRequestBody{
-		Filter: &f,
+		Filter: s.searchFilter(filter),
 		Size:   &size,
 	}
 	resp, err := s.oc.SearchProcessInstancesWithResponse(ctx, body)
@@ -122,6 +126,78 @@ func (s *Service) SearchForProcessInstances(ctx context.Context, filter processi
 	return resp.JSON200.ToStable(), nil
 }
 
+func (s *Service) SearchForProcessInstancesPage(ctx context.Context, filter processinstance.SearchFilterOpts, size int32, after camunda.Cursor) (camunda.Page[processinstance.ProcessInstance], error) {
+	body := operatev87.QueryProcessInstanceAfter{
+		Filter: s.searchFilter(filter),
+		Size:   &size,
+	}
+	if after != "" {
+		body.SearchAfter = json.RawMessage(after)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return camunda.Page[processinstance.ProcessInstance]{}, err
+	}
+	resp, err := s.oc.SearchProcessInstancesWithBodyWithResponse(ctx, jsonContentType, bytes.NewReader(b))
+	if err != nil {
+		return camunda.Page[processinstance.ProcessInstance]{}, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return camunda.Page[processinstance.ProcessInstance]{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	next, err := resp.JSON200.Cursor()
+	if err != nil {
+		return camunda.Page[processinstance.ProcessInstance]{}, fmt.Errorf("read sort values: %w", err)
+	}
+	return camunda.Page[processinstance.ProcessInstance]{
+		Total: convert.Deref(resp.JSON200.Total, 0),
+		Items: resp.JSON200.ToStable().Items,
+		Next:  camunda.Cursor(next),
+	}, nil
+}
+
+func (s *Service) searchFilter(filter processinstance.SearchFilterOpts) *operatev87.ProcessInstance {
+	return &operatev87.ProcessInstance{
+		TenantId:          &s.cfg.App.Tenant,
+		BpmnProcessId:     &filter.BpmnProcessId,
+		ProcessVersion:    convert.PtrIfNonZero(filter.ProcessVersion),
+		ProcessVersionTag: &filter.ProcessVersionTag,
+		State:             StateOrNil(filter.State),
+		ParentKey:         convert.PtrIfNonZero(filter.ParentKey),
+	}
+}
+
+func (s *Service) CreateProcessInstance(ctx context.Context, req processinstance.CreateRequest) (processinstance.CreateResponse, error) {
+	s.log.Debug(fmt.Sprintf("trying to create process instance of %s...", req.BpmnProcessId))
+	body := camundav87.CreateProcessInstanceJSONRequestBody{
+		ProcessDefinitionId:      &req.BpmnProcessId,
+		ProcessDefinitionVersion: convert.PtrIfNonZero(req.ProcessVersion),
+		TenantId:                 convert.PtrIf(s.cfg.App.Tenant, ""),
+		AwaitCompletion:          convert.PtrIf(req.AwaitCompletion, false),
+		RequestTimeout:           convert.PtrIfNonZero(req.RequestTimeout.Milliseconds()),
+	}
+	if len(req.Variables) > 0 {
+		body.Variables = &req.Variables
+	}
+	if len(req.FetchVariables) > 0 {
+		body.FetchVariables = &req.FetchVariables
+	}
+	resp, err := s.cc.CreateProcessInstanceWithResponse(ctx, body)
+	if err != nil {
+		return processinstance.CreateResponse{}, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return processinstance.CreateResponse{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	var created camundav87.CreateProcessInstanceCreated
+	if err = json.Unmarshal(resp.Body, &created); err != nil {
+		return processinstance.CreateResponse{}, fmt.Errorf("decode created process instance: %w", err)
+	}
+	ret := created.ToStable()
+	s.log.Info(fmt.Sprintf("process instance with key %d of %s was successfully created", ret.Key, req.BpmnProcessId))
+	return ret, nil
+}
+
 func (s *Service) CancelProcessInstance(ctx context.Context, key int64) (processinstance.CancelResponse, error) {
 	s.log.Debug(fmt.Sprintf("trying to cancel process instance with key %d...", key))
 	resp, err := s.cc.CancelProcessInstanceWithResponse(ctx, strconv.Itoa(int(key)),
@@ -136,6 +212,148 @@ func (s *Service) CancelProcessInstance(ctx context.Context, key int64) (process
 	return resp.ToStable(), nil
 }
 
+func (s *Service) GetActiveElementsOfProcessInstance(ctx context.Context, key int64) ([]processinstance.ElementInstance, error) {
+	return s.searchElementInstances(ctx, key, convert.Ptr(operatev87.FlowNodeInstanceStateACTIVE))
+}
+
+func (s *Service) GetElementInstancesOfProcessInstance(ctx context.Context, key int64) ([]processinstance.ElementInstance, error) {
+	return s.searchElementInstances(ctx, key, nil)
+}
+
+// searchElementInstances fetches all element instances of the process instance, page by page.
+func (s *Service) searchElementInstances(ctx context.Context, key int64, state *operatev87.FlowNodeInstanceState) ([]processinstance.ElementInstance, error) {
+	items, _, err := camunda.Collect(ctx, elementInstancesPageSize, 0, func(ctx context.Context, after camunda.Cursor, size int32) (camunda.Page[processinstance.ElementInstance], error) {
+		return s.searchElementInstancesPage(ctx, key, state, size, after)
+	})
+	return items, err
+}
+
+func (s *Service) searchElementInstancesPage(ctx context.Context, key int64, state *operatev87.FlowNodeInstanceState, size int32, after camunda.Cursor) (camunda.Page[processinstance.ElementInstance], error) {
+	body := operatev87.QueryFlowNodeInstanceAfter{
+		Filter: &operatev87.FlowNodeInstance{
+			ProcessInstanceKey: &key,
+			State:              state,
+		},
+		Size: &size,
+	}
+	if after != "" {
+		body.SearchAfter = json.RawMessage(after)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return camunda.Page[processinstance.ElementInstance]{}, err
+	}
+	resp, err := s.oc.SearchFlownodeInstancesWithBodyWithResponse(ctx, jsonContentType, bytes.NewReader(b))
+	if err != nil {
+		return camunda.Page[processinstance.ElementInstance]{}, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return camunda.Page[processinstance.ElementInstance]{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	next, err := resp.JSON200.Cursor()
+	if err != nil {
+		return camunda.Page[processinstance.ElementInstance]{}, fmt.Errorf("read sort values: %w", err)
+	}
+	return camunda.Page[processinstance.ElementInstance]{
+		Total: convert.Deref(resp.JSON200.Total, 0),
+		Items: convert.DerefSlicePtr(resp.JSON200.Items, func(f operatev87.FlowNodeInstance) processinstance.ElementInstance {
+			return f.ToStable()
+		}),
+		Next: camunda.Cursor(next),
+	}, nil
+}
+
+// GetSequenceFlowsOfProcessInstance returns the IDs of the sequence flows taken. The generated response
+// has no JSON200, the body is a plain array of IDs.
+func (s *Service) GetSequenceFlowsOfProcessInstance(ctx context.Context, key int64) ([]string, error) {
+	resp, err := s.oc.GetSequenceFlowsOfProcessInstanceByKeyWithResponse(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	var flows []string
+	if err = json.Unmarshal(resp.Body, &flows); err != nil {
+		return nil, fmt.Errorf("decode sequence flows: %w", err)
+	}
+	return flows, nil
+}
+
+func (s *Service) GetElementStatisticsOfProcessInstance(ctx context.Context, key int64) ([]processinstance.ElementStatistics, error) {
+	resp, err := s.oc.GetFlowNodeStatisticByProcessInstanceIdWithResponse(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return convert.DerefSlicePtr(resp.JSON200, func(f operatev87.FlowNodeStatistics) processinstance.ElementStatistics {
+		return f.ToStable()
+	}), nil
+}
+
+func (s *Service) MigrateProcessInstance(ctx context.Context, key int64, plan processinstance.MigrationPlan) (processinstance.MigrateResponse, error) {
+	s.log.Debug(fmt.Sprintf("trying to migrate process instance with key %d to process definition %d...", key, plan.TargetProcessDefinitionKey))
+	body := camundav87.ProcessInstanceMigration{
+		TargetProcessDefinitionKey: strconv.FormatInt(plan.TargetProcessDefinitionKey, 10),
+		MappingInstructions: convert.MapSlice(plan.MappingInstructions, func(m processinstance.MappingInstruction) camundav87.MigrateProcessInstanceMappingInstruction {
+			return camundav87.MigrateProcessInstanceMappingInstruction{SourceElementId: m.SourceElementId, TargetElementId: m.TargetElementId}
+		}),
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return processinstance.MigrateResponse{}, err
+	}
+	resp, err := s.cc.MigrateProcessInstanceWithBodyWithResponse(ctx, strconv.FormatInt(key, 10), jsonContentType, bytes.NewReader(b))
+	if err != nil {
+		return processinstance.MigrateResponse{}, err
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return processinstance.MigrateResponse{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	s.log.Info(fmt.Sprintf("process instance with key %d was successfully migrated", key))
+	return resp.ToStable(), nil
+}
+
+func (s *Service) ModifyProcessInstance(ctx context.Context, key int64, plan processinstance.ModificationPlan) (processinstance.ModifyResponse, error) {
+	s.log.Debug(fmt.Sprintf("trying to modify process instance with key %d...", key))
+	body := camundav87.ProcessInstanceModification{
+		ActivateInstructions: make([]camundav87.ProcessInstanceModificationActivation, 0, len(plan.Activate)),
+		TerminateInstructions: convert.MapSlice(plan.Terminate, func(k int64) camundav87.ProcessInstanceModificationTerminateInstruction {
+			return camundav87.ProcessInstanceModificationTerminateInstruction{ElementInstanceKey: strconv.FormatInt(k, 10)}
+		}),
+	}
+	for i, a := range plan.Activate {
+		ai := camundav87.ProcessInstanceModificationActivation{ElementId: a.ElementId}
+		if a.AncestorElementInstanceKey > 0 {
+			ai.AncestorElementInstanceKey = convert.Ptr(strconv.FormatInt(a.AncestorElementInstanceKey, 10))
+		}
+		if i == 0 && len(plan.Variables) > 0 {
+			ai.VariableInstructions = &[]camundav87.ModifyProcessInstanceVariableInstruction{{Variables: plan.Variables}}
+		}
+		body.ActivateInstructions = append(body.ActivateInstructions, ai)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return processinstance.ModifyResponse{}, err
+	}
+	resp, err := s.cc.ModifyProcessInstanceWithBodyWithResponse(ctx, strconv.FormatInt(key, 10), jsonContentType, bytes.NewReader(b))
+	if err != nil {
+		return processinstance.ModifyResponse{}, err
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return processinstance.ModifyResponse{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	s.log.Info(fmt.Sprintf("process instance with key %d was successfully modified", key))
+	return resp.ToStable(), nil
+}
+
+// ModifyProcessInstances is not supported, 8.7 has no batch modification; modify the instances one by one.
+func (s *Service) ModifyProcessInstances(ctx context.Context, filter processinstance.SearchFilterOpts, moves []processinstance.MoveInstruction) (processinstance.BatchOperation, error) {
+	return processinstance.BatchOperation{}, camunda.ErrNotSupported
+}
+
 func (s *Service) DeleteProcessInstance(ctx context.Context, key int64) (processinstance.ChangeStatus, error) {
 	s.log.Debug(fmt.Sprintf("trying to delete process instance with key %d...", key))
 	resp, err := s.oc.DeleteProcessInstanceAndAllDependantDataByKeyWithResponse(ctx, key)
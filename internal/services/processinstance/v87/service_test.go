@@ -0,0 +1,44 @@
+package v87_test
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v87 "github.com/grafvonb/camunder/internal/services/processinstance/v87"
+	"github.com/grafvonb/camunder/internal/testx"
+	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService(t *testing.T, h http.Handler) *v87.Service {
+	t.Helper()
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+
+	cfg := testx.TestConfig()
+	cfg.App.Tenant = ""
+	cfg.APIs.Camunda.BaseURL = ts.URL + "/v2"
+	cfg.APIs.Operate.BaseURL = ts.URL
+	svc, err := v87.New(cfg, ts.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	return svc
+}
+
+func TestService_GetProcessInstanceByKey_NotFound(t *testing.T) {
+	svc := newTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/process-instances/1", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"status":404,"message":"No process instances found for key 1"}`)
+	}))
+
+	_, err := svc.GetProcessInstanceByKey(t.Context(), 1)
+	require.ErrorIs(t, err, processinstance.ErrNotFound)
+
+	o, err := processinstance.Observe(t.Context(), svc, nil, 1, processinstance.Condition{State: processinstance.StateAbsent})
+	require.NoError(t, err)
+	require.True(t, o.Absent)
+}
@@ -8,6 +8,11 @@ import (
 	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
 )
 
+// AncestryStrategy is by parent key, 8.7 has no call hierarchy endpoint.
+func (s *Service) AncestryStrategy() processinstance.AncestryStrategy {
+	return processinstance.AncestryByParentKey
+}
+
 func (s *Service) Ancestry(ctx context.Context, startKey int64) (rootKey int64, path []int64, chain map[int64]processinstance.ProcessInstance, err error) {
 	// visited keeps track of visited nodes to detect cycles
 	// well-know pattern to have fast lookups, no duplicates, clear semantic and low memory usage with visited[cur] = struct{}{} below
@@ -45,70 +50,15 @@ func (s *Service) Ancestry(ctx context.Context, startKey int64) (rootKey int64,
 	}
 }
 
-func (s *Service) Descendants(ctx context.Context, rootKey int64) (desc []int64, edges map[int64][]int64, chain map[int64]processinstance.ProcessInstance, err error) {
-	visited := make(map[int64]struct{})
-	edges = make(map[int64][]int64)
-	chain = make(map[int64]processinstance.ProcessInstance)
-
-	// depth-first search (DFS) to explore the tree
-	var dfs func(int64) error
-	dfs = func(parent int64) error {
-		// check for context cancellation
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		if _, seen := visited[parent]; seen {
-			// already expanded this subtree
-			return nil
-		}
-		visited[parent] = struct{}{}
-
-		desc = append(desc, parent)
-		if _, ok := chain[parent]; !ok {
-			it, getErr := s.GetProcessInstanceByKey(ctx, parent)
-			if getErr != nil {
-				return fmt.Errorf("get %d: %w", parent, getErr)
-			}
-			chain[parent] = it
-		}
-
-		children, e := s.GetDirectChildrenOfProcessInstance(ctx, parent)
-		if e != nil {
-			return fmt.Errorf("list children of %d: %w", parent, e)
-		}
-
-		// keep an entry even if no children (useful for tree rendering)
-		if _, ok := edges[parent]; !ok {
-			edges[parent] = nil
-		}
-
-		for i := range children.Items {
-			it := children.Items[i]
-			k := it.Key
-
-			edges[parent] = append(edges[parent], k)
-			chain[k] = it
-
-			if dfsErr := dfs(k); dfsErr != nil {
-				return dfsErr
-			}
-		}
-		return nil
-	}
-
-	if err = dfs(rootKey); err != nil {
-		return nil, nil, nil, err
-	}
-	return desc, edges, chain, nil
+// Descendants walks the tree below rootKey, see processinstance.WalkDescendants.
+func (s *Service) Descendants(ctx context.Context, rootKey int64, opts ...processinstance.WalkOption) (desc []int64, edges map[int64][]int64, chain map[int64]processinstance.ProcessInstance, err error) {
+	return processinstance.WalkDescendants(ctx, s, rootKey, opts...)
 }
 
-func (s *Service) Family(ctx context.Context, startKey int64) (fam []int64, edges map[int64][]int64, chain map[int64]processinstance.ProcessInstance, err error) {
+func (s *Service) Family(ctx context.Context, startKey int64, opts ...processinstance.WalkOption) (fam []int64, edges map[int64][]int64, chain map[int64]processinstance.ProcessInstance, err error) {
 	rootKey, _, _, err := s.Ancestry(ctx, startKey)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("ancestry fetch: %w", err)
 	}
-	return s.Descendants(ctx, rootKey)
+	return s.Descendants(ctx, rootKey, opts...)
 }
@@ -1,10 +1,15 @@
 package v88
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 
+	"github.com/grafvonb/camunder/internal/api/convert"
 	camundav88 "github.com/grafvonb/camunder/internal/api/gen/clients/camunda/camunda/v88"
 	operatev88 "github.com/grafvonb/camunder/internal/api/gen/clients/camunda/operate/v88"
 	"github.com/grafvonb/camunder/internal/config"
@@ -12,7 +17,16 @@ import (
 	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
 )
 
-// nolint
+const (
+	wrongStateMessage400 = "Process instances needs to be in one of the states [COMPLETED, CANCELED]"
+	jsonContentType      = "application/json"
+	// elementInstancesPageSize is the page size of the element instance searches.
+	elementInstancesPageSize int32 = 1000
+	// childrenPageSize is the page size of the children searches of a walk.
+	childrenPageSize   int32 = 1000
+	elementStateActive       = "ACTIVE"
+)
+
 type Service struct {
 	cc  *camundav88.ClientWithResponses
 	oc  *operatev88.ClientWithResponses
@@ -23,41 +37,406 @@ type Service struct {
 type Option func(*Service)
 
 func New(cfg *config.Config, httpClient *http.Client, log *slog.Logger, opts ...Option) (*Service, error) {
-	panic("not implemented in v88")
+	cc, err := camundav88.NewClientWithResponses(
+		cfg.APIs.Camunda.BaseURL,
+		camundav88.WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		return nil, err
+	}
+	co, err := operatev88.NewClientWithResponses(
+		cfg.APIs.Operate.BaseURL,
+		operatev88.WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{oc: co, cc: cc, cfg: cfg, log: log}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 func (s *Service) Capabilities(ctx context.Context) camunda.Capabilities {
-	panic("not implemented in v88")
+	return camunda.Capabilities{
+		APIVersion: camunda.V88,
+	}
+}
+
+func (s *Service) FilterProcessInstanceWithOrphanParent(ctx context.Context, items []processinstance.ProcessInstance) ([]processinstance.ProcessInstance, error) {
+	if items == nil {
+		return nil, nil
+	}
+	var result []processinstance.ProcessInstance
+	for _, it := range items {
+		if it.ParentKey == 0 {
+			continue
+		}
+		_, err := s.GetProcessInstanceByKey(ctx, it.ParentKey)
+		if errors.Is(err, processinstance.ErrNotFound) {
+			result = append(result, it)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
 func (s *Service) GetProcessInstanceByKey(ctx context.Context, key int64) (processinstance.ProcessInstance, error) {
-	panic("not implemented in v88")
+	resp, err := s.cc.GetProcessInstanceWithResponse(ctx, camundav88.FormatKey(key))
+	if err != nil {
+		return processinstance.ProcessInstance{}, err
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return processinstance.ProcessInstance{}, fmt.Errorf("process instance with key %d: %w", key, processinstance.ErrNotFound)
+	}
+	if resp.StatusCode() != http.StatusOK || resp.JSON200 == nil {
+		return processinstance.ProcessInstance{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return resp.JSON200.ToStable(), nil
+}
+
+func (s *Service) GetDirectChildrenOfProcessInstance(ctx context.Context, key int64) (processinstance.ProcessInstances, error) {
+	filter := processinstance.SearchFilterOpts{
+		ParentKey: key,
+	}
+	// wide call activities (e.g. multi-instance) may have more children than fit in one page
+	items, total, err := camunda.SearchAll(ctx, s.SearchForProcessInstancesPage, filter, childrenPageSize, 0)
+	if err != nil {
+		return processinstance.ProcessInstances{}, fmt.Errorf("searching for children of process instance with key %d: %w", key, err)
+	}
+	return processinstance.ProcessInstances{Total: int32(total), Items: items}, nil
+}
+
+func (s *Service) SearchForProcessInstances(ctx context.Context, filter processinstance.SearchFilterOpts, size int32) (processinstance.ProcessInstances, error) {
+	var page camundav88.SearchQueryPageRequest
+	if err := page.FromOffsetPagination(camundav88.OffsetPagination{Limit: &size}); err != nil {
+		return processinstance.ProcessInstances{}, err
+	}
+	result, err := s.search(ctx, filter, page)
+	if err != nil {
+		return processinstance.ProcessInstances{}, err
+	}
+	return result.ToStable(), nil
+}
+
+func (s *Service) SearchForProcessInstancesPage(ctx context.Context, filter processinstance.SearchFilterOpts, size int32, after camunda.Cursor) (camunda.Page[processinstance.ProcessInstance], error) {
+	var page camundav88.SearchQueryPageRequest
+	var err error
+	if after == "" {
+		err = page.FromOffsetPagination(camundav88.OffsetPagination{Limit: &size})
+	} else {
+		err = page.FromCursorForwardPagination(camundav88.CursorForwardPagination{After: string(after), Limit: &size})
+	}
+	if err != nil {
+		return camunda.Page[processinstance.ProcessInstance]{}, err
+	}
+	result, err := s.search(ctx, filter, page)
+	if err != nil {
+		return camunda.Page[processinstance.ProcessInstance]{}, err
+	}
+	var next camunda.Cursor
+	if result.Page.EndCursor != nil && *result.Page.EndCursor != nil {
+		next = camunda.Cursor(fmt.Sprint(*result.Page.EndCursor))
+	}
+	return camunda.Page[processinstance.ProcessInstance]{
+		Total: int64(result.Page.TotalItems),
+		Items: result.ToStable().Items,
+		Next:  next,
+	}, nil
+}
+
+func (s *Service) search(ctx context.Context, filter processinstance.SearchFilterOpts, page camundav88.SearchQueryPageRequest) (*camundav88.ProcessInstanceQueryResult, error) {
+	f := s.searchFilter(filter)
+	body, err := json.Marshal(camundav88.ProcessInstanceQuery{Filter: &f, Page: &page})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.cc.SearchProcessInstancesWithBodyWithResponse(ctx, jsonContentType, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	var result camundav88.ProcessInstanceQueryResult
+	if err = json.Unmarshal(resp.Body, &result); err != nil {
+		return nil, fmt.Errorf("decode search result: %w", err)
+	}
+	return &result, nil
+}
+
+func (s *Service) searchFilter(filter processinstance.SearchFilterOpts) camundav88.ProcessInstanceQueryFilter {
+	f := camundav88.ProcessInstanceQueryFilter{
+		TenantId:                    convert.PtrIf(s.cfg.App.Tenant, ""),
+		ProcessDefinitionId:         convert.PtrIf(filter.BpmnProcessId, ""),
+		ProcessDefinitionVersion:    convert.PtrIfNonZero(filter.ProcessVersion),
+		ProcessDefinitionVersionTag: convert.PtrIf(filter.ProcessVersionTag, ""),
+		State:                       camundav88.StateFromStable(filter.State),
+	}
+	if filter.Key > 0 {
+		f.ProcessInstanceKey = convert.Ptr(camundav88.FormatKey(filter.Key))
+	}
+	if filter.ParentKey > 0 {
+		f.ParentProcessInstanceKey = convert.Ptr(camundav88.FormatKey(filter.ParentKey))
+	}
+	return f
 }
 
-func (s *Service) WaitForProcessInstanceState(ctx context.Context, key int64, desiredState processinstance.State) error {
-	panic("not implemented in v88")
+func (s *Service) CreateProcessInstance(ctx context.Context, req processinstance.CreateRequest) (processinstance.CreateResponse, error) {
+	s.log.Debug(fmt.Sprintf("trying to create process instance of %s...", req.BpmnProcessId))
+	byID := camundav88.ProcessInstanceCreationInstructionById{
+		ProcessDefinitionId:      req.BpmnProcessId,
+		ProcessDefinitionVersion: convert.PtrIfNonZero(req.ProcessVersion),
+		TenantId:                 convert.PtrIf(s.cfg.App.Tenant, ""),
+		AwaitCompletion:          convert.PtrIf(req.AwaitCompletion, false),
+		RequestTimeout:           convert.PtrIfNonZero(int(req.RequestTimeout.Milliseconds())),
+	}
+	if len(req.Variables) > 0 {
+		byID.Variables = &req.Variables
+	}
+	if len(req.FetchVariables) > 0 {
+		byID.FetchVariables = &req.FetchVariables
+	}
+	var body camundav88.CreateProcessInstanceJSONRequestBody
+	if err := body.FromProcessInstanceCreationInstructionById(byID); err != nil {
+		return processinstance.CreateResponse{}, err
+	}
+	resp, err := s.cc.CreateProcessInstanceWithResponse(ctx, body)
+	if err != nil {
+		return processinstance.CreateResponse{}, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return processinstance.CreateResponse{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	ret := resp.JSON200.ToStable()
+	s.log.Info(fmt.Sprintf("process instance with key %d of %s was successfully created", ret.Key, req.BpmnProcessId))
+	return ret, nil
 }
 
 func (s *Service) CancelProcessInstance(ctx context.Context, key int64) (processinstance.CancelResponse, error) {
-	panic("not implemented in v88")
+	s.log.Debug(fmt.Sprintf("trying to cancel process instance with key %d...", key))
+	resp, err := s.cc.CancelProcessInstanceWithResponse(ctx, camundav88.FormatKey(key),
+		camundav88.CancelProcessInstanceJSONRequestBody{})
+	if err != nil {
+		return processinstance.CancelResponse{}, err
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return processinstance.CancelResponse{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	s.log.Info(fmt.Sprintf("process instance with key %d was successfully cancelled", key))
+	return resp.ToStable(), nil
 }
 
-func (s *Service) SearchForProcessInstances(ctx context.Context, filter processinstance.SearchFilterOpts, size int32) (processinstance.ProcessInstances, error) {
-	panic("not implemented in v88")
+func (s *Service) GetActiveElementsOfProcessInstance(ctx context.Context, key int64) ([]processinstance.ElementInstance, error) {
+	return s.searchElementInstances(ctx, key, convert.Ptr(elementStateActive))
 }
 
-func (s *Service) GetDirectChildrenOfProcessInstance(ctx context.Context, key int64) (processinstance.ProcessInstances, error) {
-	panic("not implemented in v88")
+func (s *Service) GetElementInstancesOfProcessInstance(ctx context.Context, key int64) ([]processinstance.ElementInstance, error) {
+	return s.searchElementInstances(ctx, key, nil)
 }
 
-func (s *Service) DeleteProcessInstance(ctx context.Context, key int64) (processinstance.ChangeStatus, error) {
-	panic("not implemented in v88")
+// searchElementInstances fetches all element instances of the process instance, page by page.
+func (s *Service) searchElementInstances(ctx context.Context, key int64, state *string) ([]processinstance.ElementInstance, error) {
+	items, _, err := camunda.Collect(ctx, elementInstancesPageSize, 0, func(ctx context.Context, after camunda.Cursor, size int32) (camunda.Page[processinstance.ElementInstance], error) {
+		return s.searchElementInstancesPage(ctx, key, state, size, after)
+	})
+	return items, err
 }
 
-func (s *Service) FilterProcessInstanceWithOrphanParent(ctx context.Context, items []processinstance.ProcessInstance) ([]processinstance.ProcessInstance, error) {
-	panic("not implemented in v88")
+func (s *Service) searchElementInstancesPage(ctx context.Context, key int64, state *string, size int32, after camunda.Cursor) (camunda.Page[processinstance.ElementInstance], error) {
+	var page camundav88.SearchQueryPageRequest
+	var err error
+	if after == "" {
+		err = page.FromOffsetPagination(camundav88.OffsetPagination{Limit: &size})
+	} else {
+		err = page.FromCursorForwardPagination(camundav88.CursorForwardPagination{After: string(after), Limit: &size})
+	}
+	if err != nil {
+		return camunda.Page[processinstance.ElementInstance]{}, err
+	}
+	body, err := json.Marshal(camundav88.ElementInstanceQuery{
+		Filter: &camundav88.ElementInstanceQueryFilter{
+			ProcessInstanceKey: convert.Ptr(camundav88.FormatKey(key)),
+			State:              state,
+		},
+		Page: &page,
+	})
+	if err != nil {
+		return camunda.Page[processinstance.ElementInstance]{}, err
+	}
+	resp, err := s.cc.SearchElementInstancesWithBodyWithResponse(ctx, jsonContentType, bytes.NewReader(body))
+	if err != nil {
+		return camunda.Page[processinstance.ElementInstance]{}, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return camunda.Page[processinstance.ElementInstance]{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	var result camundav88.ElementInstanceQueryResult
+	if err = json.Unmarshal(resp.Body, &result); err != nil {
+		return camunda.Page[processinstance.ElementInstance]{}, fmt.Errorf("decode search result: %w", err)
+	}
+	var next camunda.Cursor
+	if result.Page.EndCursor != nil && *result.Page.EndCursor != nil {
+		next = camunda.Cursor(fmt.Sprint(*result.Page.EndCursor))
+	}
+	return camunda.Page[processinstance.ElementInstance]{
+		Total: int64(result.Page.TotalItems),
+		Items: convert.MapSlice(result.Items, func(e camundav88.ElementInstanceResult) processinstance.ElementInstance {
+			return e.ToStable()
+		}),
+		Next: next,
+	}, nil
+}
+
+func (s *Service) GetSequenceFlowsOfProcessInstance(ctx context.Context, key int64) ([]string, error) {
+	resp, err := s.cc.GetProcessInstanceSequenceFlowsWithResponse(ctx, camundav88.FormatKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != http.StatusOK || resp.JSON200 == nil {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return convert.DerefSlicePtr(resp.JSON200.Items, func(f camundav88.ProcessInstanceSequenceFlowResult) string {
+		return convert.Deref(f.SequenceFlowId, "")
+	}), nil
+}
+
+func (s *Service) GetElementStatisticsOfProcessInstance(ctx context.Context, key int64) ([]processinstance.ElementStatistics, error) {
+	resp, err := s.cc.GetProcessInstanceStatisticsWithResponse(ctx, camundav88.FormatKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != http.StatusOK || resp.JSON200 == nil {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return convert.DerefSlicePtr(resp.JSON200.Items, func(e camundav88.ProcessElementStatisticsResult) processinstance.ElementStatistics {
+		return e.ToStable()
+	}), nil
+}
+
+// MigrateProcessInstance migrates a single instance. The 8.8 batch migration is not used, as it
+// reports no per-instance results.
+func (s *Service) MigrateProcessInstance(ctx context.Context, key int64, plan processinstance.MigrationPlan) (processinstance.MigrateResponse, error) {
+	s.log.Debug(fmt.Sprintf("trying to migrate process instance with key %d to process definition %d...", key, plan.TargetProcessDefinitionKey))
+	resp, err := s.cc.MigrateProcessInstanceWithResponse(ctx, camundav88.FormatKey(key), camundav88.MigrateProcessInstanceJSONRequestBody{
+		TargetProcessDefinitionKey: camundav88.FormatKey(plan.TargetProcessDefinitionKey),
+		MappingInstructions: convert.MapSlice(plan.MappingInstructions, func(m processinstance.MappingInstruction) camundav88.MigrateProcessInstanceMappingInstruction {
+			return camundav88.MigrateProcessInstanceMappingInstruction{SourceElementId: m.SourceElementId, TargetElementId: m.TargetElementId}
+		}),
+	})
+	if err != nil {
+		return processinstance.MigrateResponse{}, err
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return processinstance.MigrateResponse{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	s.log.Info(fmt.Sprintf("process instance with key %d was successfully migrated", key))
+	return resp.ToStable(), nil
+}
+
+func (s *Service) ModifyProcessInstance(ctx context.Context, key int64, plan processinstance.ModificationPlan) (processinstance.ModifyResponse, error) {
+	s.log.Debug(fmt.Sprintf("trying to modify process instance with key %d...", key))
+	var body camundav88.ModifyProcessInstanceJSONRequestBody
+	if len(plan.Activate) > 0 {
+		activate := make([]camundav88.ProcessInstanceModificationActivateInstruction, 0, len(plan.Activate))
+		for i, a := range plan.Activate {
+			ai := camundav88.ProcessInstanceModificationActivateInstruction{ElementId: a.ElementId}
+			if a.AncestorElementInstanceKey > 0 {
+				ai.AncestorElementInstanceKey = &camundav88.ProcessInstanceModificationActivateInstruction_AncestorElementInstanceKey{}
+				if err := ai.AncestorElementInstanceKey.FromElementInstanceKey(camundav88.FormatKey(a.AncestorElementInstanceKey)); err != nil {
+					return processinstance.ModifyResponse{}, err
+				}
+			}
+			if i == 0 && len(plan.Variables) > 0 {
+				ai.VariableInstructions = &[]camundav88.ModifyProcessInstanceVariableInstruction{{Variables: plan.Variables}}
+			}
+			activate = append(activate, ai)
+		}
+		body.ActivateInstructions = &activate
+	}
+	if len(plan.Terminate) > 0 {
+		body.TerminateInstructions = convert.Ptr(convert.MapSlice(plan.Terminate, func(k int64) camundav88.ProcessInstanceModificationTerminateInstruction {
+			return camundav88.ProcessInstanceModificationTerminateInstruction{ElementInstanceKey: camundav88.FormatKey(k)}
+		}))
+	}
+	resp, err := s.cc.ModifyProcessInstanceWithResponse(ctx, camundav88.FormatKey(key), body)
+	if err != nil {
+		return processinstance.ModifyResponse{}, err
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return processinstance.ModifyResponse{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	s.log.Info(fmt.Sprintf("process instance with key %d was successfully modified", key))
+	return resp.ToStable(), nil
+}
+
+// ModifyProcessInstances creates a batch operation that moves the tokens of all process instances
+// matching the filter. The engine processes it asynchronously.
+func (s *Service) ModifyProcessInstances(ctx context.Context, filter processinstance.SearchFilterOpts, moves []processinstance.MoveInstruction) (processinstance.BatchOperation, error) {
+	s.log.Debug(fmt.Sprintf("trying to create a batch operation to modify process instances with %d move instruction(s)...", len(moves)))
+	body, err := json.Marshal(camundav88.ProcessInstanceModificationBatch{
+		Filter: s.searchFilter(filter),
+		MoveInstructions: convert.MapSlice(moves, func(m processinstance.MoveInstruction) camundav88.ProcessInstanceModificationMoveBatchOperationInstruction {
+			return camundav88.ProcessInstanceModificationMoveBatchOperationInstruction{SourceElementId: m.SourceElementId, TargetElementId: m.TargetElementId}
+		}),
+	})
+	if err != nil {
+		return processinstance.BatchOperation{}, err
+	}
+	resp, err := s.cc.CreateABatchOperationToModifyProcessInstancesWithBodyWithResponse(ctx, jsonContentType, bytes.NewReader(body))
+	if err != nil {
+		return processinstance.BatchOperation{}, err
+	}
+	if resp.StatusCode() != http.StatusOK || resp.JSON200 == nil {
+		return processinstance.BatchOperation{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	ret := resp.JSON200.ToStable()
+	s.log.Info(fmt.Sprintf("batch operation with key %s to modify process instances was successfully created", ret.Key))
+	return ret, nil
+}
+
+// DeleteProcessInstance uses the Operate API, the 8.8 Camunda API has no endpoint to delete a single process instance.
+func (s *Service) DeleteProcessInstance(ctx context.Context, key int64) (processinstance.ChangeStatus, error) {
+	s.log.Debug(fmt.Sprintf("trying to delete process instance with key %d...", key))
+	resp, err := s.oc.DeleteProcessInstanceAndAllDependantDataByKeyWithResponse(ctx, key)
+	if err != nil {
+		return processinstance.ChangeStatus{}, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return processinstance.ChangeStatus{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	s.log.Info(fmt.Sprintf("process instance with key %d was successfully deleted", key))
+	ret := resp.JSON200.ToStable()
+	return ret, nil
 }
 
 func (s *Service) DeleteProcessInstanceWithCancel(ctx context.Context, key int64) (processinstance.ChangeStatus, error) {
-	panic("not implemented in v88")
+	s.log.Debug(fmt.Sprintf("trying to delete process instance with key %d...", key))
+	resp, err := s.oc.DeleteProcessInstanceAndAllDependantDataByKeyWithResponse(ctx, key)
+	if err == nil &&
+		resp.StatusCode() == http.StatusBadRequest &&
+		resp.ApplicationproblemJSON400 != nil &&
+		convert.Deref(resp.ApplicationproblemJSON400.Message, "") == wrongStateMessage400 {
+		s.log.Info(fmt.Sprintf("process instance with key %d not in state COMPLETED or CANCELED, cancelling it first...", key))
+		_, err = s.CancelProcessInstance(ctx, key)
+		if err != nil {
+			return processinstance.ChangeStatus{}, fmt.Errorf("error cancelling process instance with key %d: %w", key, err)
+		}
+		s.log.Info(fmt.Sprintf("waiting for process instance with key %d to be cancelled by workflow engine...", key))
+		if err = s.WaitForProcessInstanceState(ctx, key, processinstance.StateCanceled); err != nil {
+			return processinstance.ChangeStatus{}, fmt.Errorf("waiting for canceled state failed for %d: %w", key, err)
+		}
+		resp, err = s.oc.DeleteProcessInstanceAndAllDependantDataByKeyWithResponse(ctx, key)
+	}
+	if err != nil {
+		return processinstance.ChangeStatus{}, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return processinstance.ChangeStatus{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	s.log.Info(fmt.Sprintf("process instance with key %d was successfully deleted", key))
+	ret := resp.JSON200.ToStable()
+	return ret, nil
 }
@@ -0,0 +1,262 @@
+package v88_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v88 "github.com/grafvonb/camunder/internal/services/processinstance/v88"
+	"github.com/grafvonb/camunder/internal/testx"
+	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
+	"github.com/stretchr/testify/require"
+)
+
+const processInstanceJSON = `{
+	"processInstanceKey": "2251799813685251",
+	"processDefinitionId": "order-process",
+	"processDefinitionKey": "2251799813685249",
+	"processDefinitionName": "Order",
+	"processDefinitionVersion": 3,
+	"parentProcessInstanceKey": "2251799813685100",
+	"startDate": "2025-09-01T10:00:00Z",
+	"endDate": "2025-09-01T10:05:00Z",
+	"state": "TERMINATED",
+	"hasIncident": true,
+	"tenantId": "<default>",
+	"tags": []
+}`
+
+func newTestService(t *testing.T, h http.Handler) *v88.Service {
+	t.Helper()
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+
+	cfg := testx.TestConfig()
+	cfg.App.Tenant = ""
+	cfg.APIs.Camunda.BaseURL = ts.URL + "/v2"
+	cfg.APIs.Operate.BaseURL = ts.URL + "/v1"
+	svc, err := v88.New(cfg, ts.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	return svc
+}
+
+func TestService_GetProcessInstanceByKey(t *testing.T) {
+	svc := newTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/process-instances/2251799813685251", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, processInstanceJSON)
+	}))
+
+	pi, err := svc.GetProcessInstanceByKey(t.Context(), 2251799813685251)
+	require.NoError(t, err)
+	require.Equal(t, int64(2251799813685251), pi.Key)
+	require.Equal(t, "order-process", pi.BpmnProcessId)
+	require.Equal(t, int64(2251799813685100), pi.ParentKey)
+	require.Equal(t, int32(3), pi.ProcessVersion)
+	require.True(t, pi.State.EqualsIgnoreCase(processinstance.StateCanceled))
+	require.True(t, pi.Incident)
+}
+
+func TestService_GetProcessInstanceByKey_NotFound(t *testing.T) {
+	svc := newTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"status":404,"title":"NOT_FOUND"}`)
+	}))
+
+	_, err := svc.GetProcessInstanceByKey(t.Context(), 1)
+	require.ErrorIs(t, err, processinstance.ErrNotFound)
+
+	o, err := processinstance.Observe(t.Context(), svc, nil, 1, processinstance.Condition{State: processinstance.StateAbsent})
+	require.NoError(t, err)
+	require.True(t, o.Absent)
+}
+
+func TestService_SearchForProcessInstances(t *testing.T) {
+	svc := newTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/process-instances/search", r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		filter := body["filter"].(map[string]any)
+		require.Equal(t, "order-process", filter["processDefinitionId"])
+		require.Equal(t, "TERMINATED", filter["state"])
+		require.Equal(t, "2251799813685100", filter["parentProcessInstanceKey"])
+		require.NotContains(t, filter, "tenantId")
+		require.Equal(t, float64(50), body["page"].(map[string]any)["limit"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"items":[`+processInstanceJSON+`],"page":{"totalItems":1}}`)
+	}))
+
+	res, err := svc.SearchForProcessInstances(t.Context(), processinstance.SearchFilterOpts{
+		BpmnProcessId: "order-process",
+		State:         processinstance.StateCanceled,
+		ParentKey:     2251799813685100,
+	}, 50)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), res.Total)
+	require.Len(t, res.Items, 1)
+	require.Equal(t, int64(2251799813685251), res.Items[0].Key)
+}
+
+func TestService_CreateProcessInstance(t *testing.T) {
+	svc := newTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/process-instances", r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "order-process", body["processDefinitionId"])
+		require.Equal(t, float64(2), body["processDefinitionVersion"])
+		require.Equal(t, true, body["awaitCompletion"])
+		require.Equal(t, map[string]any{"orderId": "A-1"}, body["variables"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"processDefinitionId":"order-process","processDefinitionKey":"2251799813685249",
+			"processDefinitionVersion":2,"processInstanceKey":"2251799813685251","tenantId":"<default>","variables":{"approved":true}}`)
+	}))
+
+	res, err := svc.CreateProcessInstance(t.Context(), processinstance.CreateRequest{
+		BpmnProcessId:   "order-process",
+		ProcessVersion:  2,
+		Variables:       map[string]any{"orderId": "A-1"},
+		AwaitCompletion: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2251799813685251), res.Key)
+	require.Equal(t, int64(2251799813685249), res.ProcessDefinitionKey)
+	require.Equal(t, map[string]any{"approved": true}, res.Variables)
+}
+
+func TestService_ModifyProcessInstance(t *testing.T) {
+	svc := newTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/process-instances/2251799813685251/modification", r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		activate := body["activateInstructions"].([]any)[0].(map[string]any)
+		require.Equal(t, "retry_payment", activate["elementId"])
+		require.Equal(t, "2251799813685260", activate["ancestorElementInstanceKey"])
+		require.Equal(t, map[string]any{"retries": float64(3)}, activate["variableInstructions"].([]any)[0].(map[string]any)["variables"])
+		terminate := body["terminateInstructions"].([]any)[0].(map[string]any)
+		require.Equal(t, "2251799813685270", terminate["elementInstanceKey"])
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	resp, err := svc.ModifyProcessInstance(t.Context(), 2251799813685251, processinstance.ModificationPlan{
+		Activate:  []processinstance.ActivateInstruction{{ElementId: "retry_payment", AncestorElementInstanceKey: 2251799813685260}},
+		Terminate: []int64{2251799813685270},
+		Variables: map[string]any{"retries": 3},
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestService_ModifyProcessInstances(t *testing.T) {
+	svc := newTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/process-instances/modification", r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, map[string]any{"processDefinitionId": "order-process", "state": "ACTIVE"}, body["filter"])
+		require.Equal(t, []any{map[string]any{"sourceElementId": "pay", "targetElementId": "pay_v2"}}, body["moveInstructions"])
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"batchOperationKey":"2251799813685300","batchOperationType":"MODIFY_PROCESS_INSTANCE"}`)
+	}))
+
+	op, err := svc.ModifyProcessInstances(t.Context(),
+		processinstance.SearchFilterOpts{BpmnProcessId: "order-process", State: processinstance.StateActive},
+		[]processinstance.MoveInstruction{{SourceElementId: "pay", TargetElementId: "pay_v2"}})
+	require.NoError(t, err)
+	require.Equal(t, "2251799813685300", op.Key)
+	require.Equal(t, "MODIFY_PROCESS_INSTANCE", op.Type)
+}
+
+func TestService_GetSequenceFlowsAndElementStatistics(t *testing.T) {
+	svc := newTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/process-instances/2251799813685251/sequence-flows":
+			_, _ = io.WriteString(w, `{"items":[{"sequenceFlowId":"flow_start"},{"sequenceFlowId":"flow_pay"}]}`)
+		case "/v2/process-instances/2251799813685251/statistics/element-instances":
+			_, _ = io.WriteString(w, `{"items":[{"elementId":"pay","active":1,"completed":2,"canceled":0,"incidents":1}]}`)
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+
+	flows, err := svc.GetSequenceFlowsOfProcessInstance(t.Context(), 2251799813685251)
+	require.NoError(t, err)
+	require.Equal(t, []string{"flow_start", "flow_pay"}, flows)
+
+	stats, err := svc.GetElementStatisticsOfProcessInstance(t.Context(), 2251799813685251)
+	require.NoError(t, err)
+	require.Equal(t, []processinstance.ElementStatistics{{ElementId: "pay", Active: 1, Completed: 2, Incidents: 1}}, stats)
+}
+
+func TestService_GetElementInstances_FollowsPages(t *testing.T) {
+	const total = 1002
+	svc := newTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/element-instances/search", r.URL.Path)
+		var body struct {
+			Page struct {
+				After string `json:"after"`
+				Limit int    `json:"limit"`
+			} `json:"page"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		start := 0
+		if body.Page.After != "" {
+			require.Equal(t, "c1", body.Page.After)
+			start = body.Page.Limit
+		}
+		items := make([]map[string]any, 0, body.Page.Limit)
+		for i := start; i < min(start+body.Page.Limit, total); i++ {
+			items = append(items, map[string]any{"elementInstanceKey": fmt.Sprint(i + 1), "elementId": "task", "state": "COMPLETED", "type": "SERVICE_TASK"})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"items": items, "page": map[string]any{"totalItems": total, "endCursor": "c1"}}))
+	}))
+
+	elements, err := svc.GetElementInstancesOfProcessInstance(t.Context(), 2251799813685251)
+	require.NoError(t, err)
+	require.Len(t, elements, total, "more element instances than fit in one page")
+	require.Equal(t, int64(total), elements[total-1].Key)
+}
+
+func TestService_Ancestry_CallHierarchy(t *testing.T) {
+	requests := 0
+	svc := newTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.Equal(t, "/v2/process-instances/2251799813685270/call-hierarchy", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `[
+			{"processInstanceKey": "2251799813685251", "processDefinitionKey": "2251799813685249", "processDefinitionName": "Order"},
+			{"processInstanceKey": "2251799813685260", "processDefinitionKey": "2251799813685250", "processDefinitionName": "Payment"},
+			{"processInstanceKey": "2251799813685270", "processDefinitionKey": "2251799813685250", "processDefinitionName": "Payment"}
+		]`)
+	}))
+
+	require.Equal(t, processinstance.AncestryByCallHierarchy, svc.AncestryStrategy())
+	rootKey, path, chain, err := svc.Ancestry(t.Context(), 2251799813685270)
+	require.NoError(t, err)
+	require.Equal(t, 1, requests, "the whole ancestry in one request")
+	require.Equal(t, int64(2251799813685251), rootKey)
+	require.Equal(t, []int64{2251799813685270, 2251799813685260, 2251799813685251}, path)
+	require.Equal(t, int64(2251799813685260), chain[2251799813685270].ParentKey)
+	require.Equal(t, int64(2251799813685251), chain[2251799813685260].ParentKey)
+	require.Zero(t, chain[2251799813685251].ParentKey)
+	require.Equal(t, "Payment", chain[2251799813685260].ProcessDefinitionName)
+	require.Equal(t, int64(2251799813685250), chain[2251799813685260].ProcessDefinitionKey)
+}
+
+func TestService_Ancestry_NotFound(t *testing.T) {
+	svc := newTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"status":404,"title":"NOT_FOUND"}`)
+	}))
+
+	_, _, _, err := svc.Ancestry(t.Context(), 1)
+	require.ErrorIs(t, err, processinstance.ErrNotFound)
+}
@@ -0,0 +1,13 @@
+package v88
+
+import (
+	"context"
+
+	"github.com/grafvonb/camunder/internal/services/common"
+	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
+)
+
+// WaitForProcessInstanceState waits until the instance reaches the desired state, see common.WaitForProcessInstanceState.
+func (s *Service) WaitForProcessInstanceState(ctx context.Context, key int64, desiredState processinstance.State) error {
+	return common.WaitForProcessInstanceState(ctx, s.cfg.App.Backoff, s.log, key, desiredState, s.GetProcessInstanceByKey)
+}
@@ -3,112 +3,73 @@ package v88
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"slices"
 
+	camundav88 "github.com/grafvonb/camunder/internal/api/gen/clients/camunda/camunda/v88"
 	"github.com/grafvonb/camunder/pkg/camunda"
 	"github.com/grafvonb/camunder/pkg/camunda/processinstance"
 )
 
-func (s *Service) Ancestry(ctx context.Context, startKey int64) (rootKey int64, path []int64, chain map[int64]processinstance.ProcessInstance, err error) {
-	// visited keeps track of visited nodes to detect cycles
-	// well-know pattern to have fast lookups, no duplicates, clear semantic and low memory usage with visited[cur] = struct{}{} below
-	visited := make(map[int64]struct{})
-	chain = make(map[int64]processinstance.ProcessInstance)
-
-	cur := startKey
-	for {
-		// check for context cancellation
-		select {
-		case <-ctx.Done():
-			return 0, nil, chain, ctx.Err()
-		default:
-		}
-
-		if _, seen := visited[cur]; seen {
-			return 0, nil, chain, fmt.Errorf("%w for this key %d", camunda.ErrCycleDetected, cur)
-		}
-		visited[cur] = struct{}{}
-
-		it, getErr := s.GetProcessInstanceByKey(ctx, cur)
-		if getErr != nil {
-			return 0, nil, chain, fmt.Errorf("get %d: %w", cur, getErr)
-		}
-		chain[cur] = it
-		path = append(path, cur)
-
-		// no parent => cur is root
-		if it.ParentKey == 0 {
-			rootKey = cur
-			return
-		}
-
-		cur = it.ParentKey
-	}
+// AncestryStrategy is by call hierarchy, a single request whatever the depth.
+func (s *Service) AncestryStrategy() processinstance.AncestryStrategy {
+	return processinstance.AncestryByCallHierarchy
 }
 
-func (s *Service) Descendants(ctx context.Context, rootKey int64) (desc []int64, edges map[int64][]int64, chain map[int64]processinstance.ProcessInstance, err error) {
-	visited := make(map[int64]struct{})
-	edges = make(map[int64][]int64)
-	chain = make(map[int64]processinstance.ProcessInstance)
-
-	// depth-first search (DFS) to explore the tree
-	var dfs func(int64) error
-	dfs = func(parent int64) error {
-		// check for context cancellation
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		if _, seen := visited[parent]; seen {
-			// already expanded this subtree
-			return nil
-		}
-		visited[parent] = struct{}{}
-
-		desc = append(desc, parent)
-		if _, ok := chain[parent]; !ok {
-			it, getErr := s.GetProcessInstanceByKey(ctx, parent)
-			if getErr != nil {
-				return fmt.Errorf("get %d: %w", parent, getErr)
-			}
-			chain[parent] = it
-		}
-
-		children, e := s.GetDirectChildrenOfProcessInstance(ctx, parent)
-		if e != nil {
-			return fmt.Errorf("list children of %d: %w", parent, e)
-		}
-
-		// keep an entry even if no children (useful for tree rendering)
-		if _, ok := edges[parent]; !ok {
-			edges[parent] = nil
-		}
-
-		for i := range children.Items {
-			it := children.Items[i]
-			k := it.Key
-
-			edges[parent] = append(edges[parent], k)
-			chain[k] = it
+// Ancestry returns the path from startKey up to its root, from the call hierarchy of the instance.
+// It needs a single request whatever the depth, but the instances of the chain are thin, see
+// processinstance.AncestryByCallHierarchy.
+func (s *Service) Ancestry(ctx context.Context, startKey int64) (rootKey int64, path []int64, chain map[int64]processinstance.ProcessInstance, err error) {
+	resp, err := s.cc.GetCallHierarchyForProcessInstanceWithResponse(ctx, camundav88.FormatKey(startKey))
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("get call hierarchy of %d: %w", startKey, err)
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return 0, nil, nil, fmt.Errorf("get call hierarchy of %d: %w", startKey, processinstance.ErrNotFound)
+	}
+	if resp.StatusCode() != http.StatusOK || resp.JSON200 == nil {
+		return 0, nil, nil, fmt.Errorf("get call hierarchy of %d: unexpected status %d: %s", startKey, resp.StatusCode(), string(resp.Body))
+	}
+	entries := *resp.JSON200
+	if len(entries) == 0 {
+		return 0, nil, nil, fmt.Errorf("get call hierarchy of %d: %w", startKey, processinstance.ErrNotFound)
+	}
+	// the hierarchy lists the root first, down to the instance itself
+	if len(entries) > 1 && camundav88.ParseKey(entries[0].ProcessInstanceKey) == startKey {
+		entries = slices.Clone(entries)
+		slices.Reverse(entries)
+	}
 
-			if dfsErr := dfs(k); dfsErr != nil {
-				return dfsErr
-			}
+	chain = make(map[int64]processinstance.ProcessInstance, len(entries))
+	var parentKey int64
+	for _, e := range entries {
+		it := e.ToStable()
+		if _, seen := chain[it.Key]; seen {
+			return 0, nil, nil, fmt.Errorf("%w for this key %d", camunda.ErrCycleDetected, it.Key)
 		}
-		return nil
+		it.ParentKey, it.ParentProcessInstanceKey = parentKey, parentKey
+		chain[it.Key] = it
+		path = append(path, it.Key)
+		parentKey = it.Key
 	}
-
-	if err = dfs(rootKey); err != nil {
-		return nil, nil, nil, err
+	if _, ok := chain[startKey]; !ok {
+		// the instance itself is not part of the hierarchy, only its ancestors
+		chain[startKey] = processinstance.ProcessInstance{Key: startKey, ParentKey: parentKey, ParentProcessInstanceKey: parentKey}
+		path = append(path, startKey)
 	}
-	return desc, edges, chain, nil
+	slices.Reverse(path)
+	return path[len(path)-1], path, chain, nil
+}
+
+// Descendants walks the tree below rootKey, see processinstance.WalkDescendants.
+func (s *Service) Descendants(ctx context.Context, rootKey int64, opts ...processinstance.WalkOption) (desc []int64, edges map[int64][]int64, chain map[int64]processinstance.ProcessInstance, err error) {
+	return processinstance.WalkDescendants(ctx, s, rootKey, opts...)
 }
 
-func (s *Service) Family(ctx context.Context, startKey int64) (fam []int64, edges map[int64][]int64, chain map[int64]processinstance.ProcessInstance, err error) {
+func (s *Service) Family(ctx context.Context, startKey int64, opts ...processinstance.WalkOption) (fam []int64, edges map[int64][]int64, chain map[int64]processinstance.ProcessInstance, err error) {
 	rootKey, _, _, err := s.Ancestry(ctx, startKey)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("ancestry fetch: %w", err)
 	}
-	return s.Descendants(ctx, rootKey)
+	return s.Descendants(ctx, rootKey, opts...)
 }
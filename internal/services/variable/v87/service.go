@@ -0,0 +1,128 @@
+package v87
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/grafvonb/camunder/internal/api/convert"
+	camundav87 "github.com/grafvonb/camunder/internal/api/gen/clients/camunda/camunda/v87"
+	operatev87 "github.com/grafvonb/camunder/internal/api/gen/clients/camunda/operate/v87"
+	"github.com/grafvonb/camunder/internal/config"
+	"github.com/grafvonb/camunder/pkg/camunda"
+	"github.com/grafvonb/camunder/pkg/camunda/variable"
+)
+
+const jsonContentType = "application/json"
+
+type Service struct {
+	cc  *camundav87.ClientWithResponses
+	oc  *operatev87.ClientWithResponses
+	cfg *config.Config
+	log *slog.Logger
+}
+
+type Option func(*Service)
+
+func New(cfg *config.Config, httpClient *http.Client, log *slog.Logger, opts ...Option) (*Service, error) {
+	cc, err := camundav87.NewClientWithResponses(
+		cfg.APIs.Camunda.BaseURL,
+		camundav87.WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		return nil, err
+	}
+	co, err := operatev87.NewClientWithResponses(
+		cfg.APIs.Operate.BaseURL,
+		operatev87.WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{oc: co, cc: cc, cfg: cfg, log: log}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+func (s *Service) Capabilities(ctx context.Context) camunda.Capabilities {
+	return camunda.Capabilities{
+		APIVersion: camunda.V87,
+	}
+}
+
+func (s *Service) GetVariableByKey(ctx context.Context, key int64) (variable.Variable, error) {
+	resp, err := s.oc.GetVariableByKeyWithResponse(ctx, key)
+	if err != nil {
+		return variable.Variable{}, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return variable.Variable{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	return resp.JSON200.ToStable(), nil
+}
+
+func (s *Service) SearchVariables(ctx context.Context, filter variable.SearchFilterOpts, size int32) (variable.Variables, error) {
+	page, err := s.SearchVariablesPage(ctx, filter, size, "")
+	if err != nil {
+		return variable.Variables{}, err
+	}
+	return variable.Variables{Total: int32(page.Total), Items: page.Items}, nil
+}
+
+func (s *Service) SearchVariablesPage(ctx context.Context, filter variable.SearchFilterOpts, size int32, after camunda.Cursor) (camunda.Page[variable.Variable], error) {
+	body := operatev87.QueryVariableAfter{
+		Filter: &operatev87.Variable{
+			TenantId:           convert.PtrIf(s.cfg.App.Tenant, ""),
+			ProcessInstanceKey: convert.PtrIfNonZero(filter.ProcessInstanceKey),
+			ScopeKey:           convert.PtrIfNonZero(filter.ScopeKey),
+			Name:               convert.PtrIf(filter.Name, ""),
+		},
+		Size: &size,
+	}
+	if after != "" {
+		body.SearchAfter = json.RawMessage(after)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return camunda.Page[variable.Variable]{}, err
+	}
+	resp, err := s.oc.SearchVariablesForProcessInstancesWithBodyWithResponse(ctx, jsonContentType, bytes.NewReader(b))
+	if err != nil {
+		return camunda.Page[variable.Variable]{}, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return camunda.Page[variable.Variable]{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	next, err := resp.JSON200.Cursor()
+	if err != nil {
+		return camunda.Page[variable.Variable]{}, fmt.Errorf("read sort values: %w", err)
+	}
+	return camunda.Page[variable.Variable]{
+		Total: convert.Deref(resp.JSON200.Total, 0),
+		Items: resp.JSON200.ToStable().Items,
+		Next:  camunda.Cursor(next),
+	}, nil
+}
+
+func (s *Service) SetVariables(ctx context.Context, elementInstanceKey int64, variables map[string]any, local bool) (variable.SetResponse, error) {
+	s.log.Debug(fmt.Sprintf("trying to set %d variable(s) on element instance with key %d...", len(variables), elementInstanceKey))
+	resp, err := s.cc.UpdateElementInstanceVariablesWithResponse(ctx, strconv.FormatInt(elementInstanceKey, 10),
+		camundav87.UpdateElementInstanceVariablesJSONRequestBody{
+			Local:     &local,
+			Variables: variables,
+		})
+	if err != nil {
+		return variable.SetResponse{}, err
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return variable.SetResponse{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	s.log.Info(fmt.Sprintf("variables of element instance with key %d were successfully set", elementInstanceKey))
+	return resp.ToStable(), nil
+}
@@ -0,0 +1,55 @@
+package v87_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v87 "github.com/grafvonb/camunder/internal/services/variable/v87"
+	"github.com/grafvonb/camunder/internal/testx"
+	"github.com/grafvonb/camunder/pkg/camunda/variable"
+	"github.com/stretchr/testify/require"
+)
+
+// orderKeyVariable holds a key, which loses its last digits as float64.
+const orderKeyVariable = `{"key":7,"name":"orderKey","value":"2251799813685251","processInstanceKey":1,"scopeKey":1,"truncated":false,"tenantId":"tenant"}`
+
+func newService(t *testing.T) *v87.Service {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/variables/7":
+			_, _ = io.WriteString(w, orderKeyVariable)
+		case "/v1/variables/search":
+			_, _ = io.WriteString(w, `{"items":[`+orderKeyVariable+`],"total":1,"sortValues":[7]}`)
+		default:
+			http.Error(w, fmt.Sprintf("unexpected path %s", r.URL.Path), http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	cfg := testx.TestConfig()
+	cfg.APIs.Camunda.BaseURL = ts.URL + "/v2"
+	cfg.APIs.Operate.BaseURL = ts.URL
+	svc, err := v87.New(cfg, ts.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	return svc
+}
+
+func TestService_GetVariableByKey_KeepsLargeIntegers(t *testing.T) {
+	v, err := newService(t).GetVariableByKey(t.Context(), 7)
+	require.NoError(t, err)
+	require.Equal(t, json.Number("2251799813685251"), v.Value)
+}
+
+func TestService_SearchVariablesPage_KeepsLargeIntegers(t *testing.T) {
+	page, err := newService(t).SearchVariablesPage(t.Context(), variable.SearchFilterOpts{ProcessInstanceKey: 1}, 10, "")
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, json.Number("2251799813685251"), page.Items[0].Value)
+	require.Equal(t, int64(1), page.Total)
+}
@@ -0,0 +1,146 @@
+package v88
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/grafvonb/camunder/internal/api/convert"
+	camundav88 "github.com/grafvonb/camunder/internal/api/gen/clients/camunda/camunda/v88"
+	"github.com/grafvonb/camunder/internal/config"
+	"github.com/grafvonb/camunder/pkg/camunda"
+	"github.com/grafvonb/camunder/pkg/camunda/variable"
+)
+
+const jsonContentType = "application/json"
+
+type Service struct {
+	cc  *camundav88.ClientWithResponses
+	cfg *config.Config
+	log *slog.Logger
+}
+
+type Option func(*Service)
+
+func New(cfg *config.Config, httpClient *http.Client, log *slog.Logger, opts ...Option) (*Service, error) {
+	cc, err := camundav88.NewClientWithResponses(
+		cfg.APIs.Camunda.BaseURL,
+		camundav88.WithHTTPClient(httpClient),
+	)
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{cc: cc, cfg: cfg, log: log}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+func (s *Service) Capabilities(ctx context.Context) camunda.Capabilities {
+	return camunda.Capabilities{
+		APIVersion: camunda.V88,
+	}
+}
+
+func (s *Service) GetVariableByKey(ctx context.Context, key int64) (variable.Variable, error) {
+	resp, err := s.cc.GetVariableWithResponse(ctx, camundav88.FormatKey(key))
+	if err != nil {
+		return variable.Variable{}, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return variable.Variable{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	var result camundav88.VariableValueResult
+	if err = json.Unmarshal(resp.Body, &result); err != nil {
+		return variable.Variable{}, fmt.Errorf("decode variable: %w", err)
+	}
+	return result.ToStable(), nil
+}
+
+func (s *Service) SearchVariables(ctx context.Context, filter variable.SearchFilterOpts, size int32) (variable.Variables, error) {
+	var page camundav88.SearchQueryPageRequest
+	if err := page.FromOffsetPagination(camundav88.OffsetPagination{Limit: &size}); err != nil {
+		return variable.Variables{}, err
+	}
+	result, err := s.search(ctx, filter, page)
+	if err != nil {
+		return variable.Variables{}, err
+	}
+	return result.ToStable(), nil
+}
+
+func (s *Service) SearchVariablesPage(ctx context.Context, filter variable.SearchFilterOpts, size int32, after camunda.Cursor) (camunda.Page[variable.Variable], error) {
+	var page camundav88.SearchQueryPageRequest
+	var err error
+	if after == "" {
+		err = page.FromOffsetPagination(camundav88.OffsetPagination{Limit: &size})
+	} else {
+		err = page.FromCursorForwardPagination(camundav88.CursorForwardPagination{After: string(after), Limit: &size})
+	}
+	if err != nil {
+		return camunda.Page[variable.Variable]{}, err
+	}
+	result, err := s.search(ctx, filter, page)
+	if err != nil {
+		return camunda.Page[variable.Variable]{}, err
+	}
+	var next camunda.Cursor
+	if result.Page.EndCursor != nil && *result.Page.EndCursor != nil {
+		next = camunda.Cursor(fmt.Sprint(*result.Page.EndCursor))
+	}
+	return camunda.Page[variable.Variable]{
+		Total: int64(result.Page.TotalItems),
+		Items: result.ToStable().Items,
+		Next:  next,
+	}, nil
+}
+
+func (s *Service) search(ctx context.Context, filter variable.SearchFilterOpts, page camundav88.SearchQueryPageRequest) (*camundav88.VariableQueryResult, error) {
+	f := camundav88.VariableQueryFilter{
+		TenantId: convert.PtrIf(s.cfg.App.Tenant, ""),
+		Name:     convert.PtrIf(filter.Name, ""),
+	}
+	if filter.ProcessInstanceKey > 0 {
+		f.ProcessInstanceKey = convert.Ptr(camundav88.FormatKey(filter.ProcessInstanceKey))
+	}
+	if filter.ScopeKey > 0 {
+		f.ScopeKey = convert.Ptr(camundav88.FormatKey(filter.ScopeKey))
+	}
+	body, err := json.Marshal(camundav88.VariableQuery{Filter: &f, Page: &page})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.cc.SearchVariablesWithBodyWithResponse(ctx, jsonContentType, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	var result camundav88.VariableQueryResult
+	if err = json.Unmarshal(resp.Body, &result); err != nil {
+		return nil, fmt.Errorf("decode search result: %w", err)
+	}
+	return &result, nil
+}
+
+func (s *Service) SetVariables(ctx context.Context, elementInstanceKey int64, variables map[string]any, local bool) (variable.SetResponse, error) {
+	s.log.Debug(fmt.Sprintf("trying to set %d variable(s) on element instance with key %d...", len(variables), elementInstanceKey))
+	resp, err := s.cc.UpdateElementInstanceVariablesWithResponse(ctx, camundav88.FormatKey(elementInstanceKey),
+		camundav88.UpdateElementInstanceVariablesJSONRequestBody{
+			Local:     &local,
+			Variables: variables,
+		})
+	if err != nil {
+		return variable.SetResponse{}, err
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return variable.SetResponse{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode(), string(resp.Body))
+	}
+	s.log.Info(fmt.Sprintf("variables of element instance with key %d were successfully set", elementInstanceKey))
+	return resp.ToStable(), nil
+}
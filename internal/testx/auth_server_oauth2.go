@@ -0,0 +1,196 @@
+package testx
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafvonb/camunder/internal/services/common"
+)
+
+const clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// AuthServerOAuth2 is a client credentials token endpoint. Clients authenticate either with
+// their secret or with a private_key_jwt client assertion, verified against PublicKey.
+type AuthServerOAuth2 struct {
+	TS       *httptest.Server
+	TokenURL string // value of auth.oauth2.token_url, the client appends /token
+	Endpoint string // the token endpoint, expected as audience of client assertions
+
+	mu     sync.Mutex
+	issued int
+	jtis   map[string]struct{}
+	opts   OAuth2AuthOpts
+}
+
+type OAuth2AuthOpts struct {
+	ClientID     string           // default "camunder"
+	ClientSecret string           // accepted secret, empty to accept assertions only
+	PublicKey    crypto.PublicKey // verifies client assertions, nil to accept secrets only
+	ExpiresIn    int              // default 300
+}
+
+func StartAuthServerOAuth2(t testing.TB, opts OAuth2AuthOpts) *AuthServerOAuth2 {
+	t.Helper()
+	opts.ClientID = common.DefaultVal(opts.ClientID, "camunder")
+	opts.ExpiresIn = common.DefaultVal(opts.ExpiresIn, 300)
+
+	s := &AuthServerOAuth2{jtis: map[string]struct{}{}, opts: opts}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", s.handleToken)
+	s.TS = httptest.NewServer(mux)
+	s.TokenURL = s.TS.URL
+	s.Endpoint = s.TS.URL + "/token"
+	return s
+}
+
+func (s *AuthServerOAuth2) Close() { s.TS.Close() }
+
+// Issued returns the number of tokens issued so far.
+func (s *AuthServerOAuth2) Issued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issued
+}
+
+func (s *AuthServerOAuth2) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+		writeOAuth2Error(w, http.StatusBadRequest, "invalid_request", "expected client_credentials grant")
+		return
+	}
+	if err := s.authenticate(r); err != nil {
+		writeOAuth2Error(w, http.StatusUnauthorized, "invalid_client", err.Error())
+		return
+	}
+	s.mu.Lock()
+	s.issued++
+	n := s.issued
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(tokenJSON200{
+		AccessToken: fmt.Sprintf("token-%d", n),
+		ExpiresIn:   s.opts.ExpiresIn,
+		TokenType:   "Bearer",
+	})
+}
+
+func (s *AuthServerOAuth2) authenticate(r *http.Request) error {
+	f := r.PostForm
+	if f.Get("client_id") != s.opts.ClientID {
+		return errors.New("unknown client")
+	}
+	if f.Has("client_assertion") {
+		if s.opts.PublicKey == nil {
+			return errors.New("client assertions are not accepted")
+		}
+		if f.Get("client_assertion_type") != clientAssertionType {
+			return errors.New("unsupported client_assertion_type")
+		}
+		return s.verifyAssertion(f.Get("client_assertion"))
+	}
+	if s.opts.ClientSecret == "" || f.Get("client_secret") != s.opts.ClientSecret {
+		return errors.New("invalid client secret")
+	}
+	return nil
+}
+
+func (s *AuthServerOAuth2) verifyAssertion(jwt string) error {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return errors.New("malformed client assertion")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	var claims struct {
+		Iss string `json:"iss"`
+		Sub string `json:"sub"`
+		Aud string `json:"aud"`
+		Jti string `json:"jti"`
+		Exp int64  `json:"exp"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return err
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errors.New("malformed signature")
+	}
+	if err := verifySignature(s.opts.PublicKey, header.Alg, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return err
+	}
+
+	switch {
+	case claims.Iss != s.opts.ClientID || claims.Sub != s.opts.ClientID:
+		return errors.New("iss and sub must be the client ID")
+	case claims.Aud != s.Endpoint:
+		return fmt.Errorf("aud %q is not the token endpoint", claims.Aud)
+	case time.Unix(claims.Exp, 0).Before(time.Now()):
+		return errors.New("client assertion expired")
+	case claims.Jti == "":
+		return errors.New("missing jti")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, seen := s.jtis[claims.Jti]; seen {
+		return errors.New("client assertion replayed")
+	}
+	s.jtis[claims.Jti] = struct{}{}
+	return nil
+}
+
+func verifySignature(key crypto.PublicKey, alg string, input, sig []byte) error {
+	digest := sha256.Sum256(input)
+	var ok bool
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		switch alg {
+		case "RS256":
+			ok = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+		case "PS256":
+			ok = rsa.VerifyPSS(k, crypto.SHA256, digest[:], sig, nil) == nil
+		}
+	case *ecdsa.PublicKey:
+		if alg == "ES256" && len(sig) == 64 {
+			r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+			ok = ecdsa.Verify(k, digest[:], r, s)
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid %s signature", alg)
+	}
+	return nil
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return errors.New("malformed client assertion")
+	}
+	return json.Unmarshal(b, v)
+}
+
+func writeOAuth2Error(w http.ResponseWriter, status int, code, desc string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code, "error_description": desc})
+}
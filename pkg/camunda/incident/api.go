@@ -0,0 +1,68 @@
+package incident
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/grafvonb/camunder/pkg/camunda"
+)
+
+var ErrNotFound = errors.New("incident not found")
+
+type API interface {
+	camunda.Base
+	GetIncidentByKey(ctx context.Context, key int64) (Incident, error)
+	SearchIncidents(ctx context.Context, filter SearchFilterOpts, size int32) (Incidents, error)
+	SearchIncidentsPage(ctx context.Context, filter SearchFilterOpts, size int32, after camunda.Cursor) (camunda.Page[Incident], error)
+	ResolveIncident(ctx context.Context, key int64) (ResolveResponse, error)
+}
+
+type Incident struct {
+	Key                  int64  `json:"key,omitempty"`
+	BpmnProcessId        string `json:"bpmnProcessId,omitempty"`
+	CreationTime         string `json:"creationTime,omitempty"`
+	ErrorType            string `json:"errorType,omitempty"`
+	ErrorMessage         string `json:"errorMessage,omitempty"`
+	JobKey               int64  `json:"jobKey,omitempty"`
+	ProcessDefinitionKey int64  `json:"processDefinitionKey,omitempty"`
+	ProcessInstanceKey   int64  `json:"processInstanceKey,omitempty"`
+	State                State  `json:"state,omitempty"`
+	TenantId             string `json:"tenantId,omitempty"`
+}
+
+type Incidents struct {
+	Total int32      `json:"total,omitempty"`
+	Items []Incident `json:"items,omitempty"`
+}
+
+type ResolveResponse struct {
+	StatusCode int
+	Status     string
+}
+
+// SearchFilterOpts selects incidents; zero values are not used as filter.
+// ErrorType is the upper-case error type reported by the engine, e.g. JOB_NO_RETRIES.
+type SearchFilterOpts struct {
+	Key                int64
+	ProcessInstanceKey int64
+	JobKey             int64
+	BpmnProcessId      string
+	ErrorType          string
+	State              State
+}
+
+// State is the incident state as reported by the engine.
+type State string
+
+const (
+	StateActive   State = "ACTIVE"
+	StatePending  State = "PENDING"
+	StateResolved State = "RESOLVED"
+)
+
+func (s State) String() string { return string(s) }
+
+func (s State) EqualsIgnoreCase(other State) bool {
+	return strings.EqualFold(string(s), string(other))
+}
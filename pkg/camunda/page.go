@@ -0,0 +1,94 @@
+package camunda
+
+import (
+	"context"
+	"iter"
+)
+
+// Cursor is an opaque pagination token. A search page returns the cursor of its last item,
+// passing it back fetches the page after it. The empty cursor requests the first page.
+type Cursor string
+
+// Page is one page of search results.
+// Total is the number of all matching items reported by the server, not only the ones in this page.
+type Page[T any] struct {
+	Total int64
+	Items []T
+	Next  Cursor // empty when there are no further pages
+}
+
+// PageFunc fetches the page of at most size items following the after cursor.
+type PageFunc[T any] func(ctx context.Context, after Cursor, size int32) (Page[T], error)
+
+// Iterate yields all items by following the cursor from page to page until the results run out.
+// The first error is yielded together with the zero item and ends the iteration.
+func Iterate[T any](ctx context.Context, pageSize int32, fetch PageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var after Cursor
+		for {
+			if err := ctx.Err(); err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			page, err := fetch(ctx, after, pageSize)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, it := range page.Items {
+				if !yield(it, nil) {
+					return
+				}
+			}
+			if page.Next == "" || page.Next == after || int32(len(page.Items)) < pageSize {
+				return
+			}
+			after = page.Next
+		}
+	}
+}
+
+// Collect gathers up to limit items (limit <= 0 means all) and returns them together with the total
+// reported by the server for the first page.
+func Collect[T any](ctx context.Context, pageSize int32, limit int, fetch PageFunc[T]) (items []T, total int64, err error) {
+	if limit > 0 && int32(limit) < pageSize {
+		pageSize = int32(limit)
+	}
+	first := true
+	counting := func(ctx context.Context, after Cursor, size int32) (Page[T], error) {
+		page, err := fetch(ctx, after, size)
+		if err == nil && first {
+			total, first = page.Total, false
+		}
+		return page, err
+	}
+	for it, e := range Iterate(ctx, pageSize, counting) {
+		if e != nil {
+			return items, total, e
+		}
+		items = append(items, it)
+		if limit > 0 && len(items) >= limit {
+			break
+		}
+	}
+	return items, total, nil
+}
+
+// SearchPageFunc is the shape of the SearchXxxPage methods of the resource APIs,
+// e.g. incident.API.SearchIncidentsPage.
+type SearchPageFunc[F, T any] func(ctx context.Context, filter F, size int32, after Cursor) (Page[T], error)
+
+// Search binds filter to a search page method, e.g. Iterate(ctx, 100, Search(api.SearchIncidentsPage, filter)).
+func Search[F, T any](search SearchPageFunc[F, T], filter F) PageFunc[T] {
+	return func(ctx context.Context, after Cursor, size int32) (Page[T], error) {
+		return search(ctx, filter, size, after)
+	}
+}
+
+// SearchAll collects up to limit items matching filter (limit <= 0 means all), fetching pages of
+// pageSize. Total is the number of all matching items reported by the server.
+func SearchAll[F, T any](ctx context.Context, search SearchPageFunc[F, T], filter F, pageSize int32, limit int) (items []T, total int64, err error) {
+	return Collect(ctx, pageSize, limit, Search(search, filter))
+}
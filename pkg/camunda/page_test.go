@@ -0,0 +1,88 @@
+package camunda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pagesOf serves items in pages, using the index of the next item as cursor.
+func pagesOf(items []int, calls *int) PageFunc[int] {
+	return func(ctx context.Context, after Cursor, size int32) (Page[int], error) {
+		*calls++
+		start := 0
+		if after != "" {
+			_, _ = fmt.Sscanf(string(after), "%d", &start)
+		}
+		end := min(start+int(size), len(items))
+		return Page[int]{
+			Total: int64(len(items)),
+			Items: items[start:end],
+			Next:  Cursor(fmt.Sprint(end)),
+		}, nil
+	}
+}
+
+func TestIterate_FollowsCursorUntilExhausted(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+	calls := 0
+	var got []int
+	for it, err := range Iterate(context.Background(), 3, pagesOf(items, &calls)) {
+		require.NoError(t, err)
+		got = append(got, it)
+	}
+	require.Equal(t, items, got)
+	require.Equal(t, 3, calls)
+}
+
+func TestIterate_StopsOnError(t *testing.T) {
+	boom := errors.New("boom")
+	fetch := func(ctx context.Context, after Cursor, size int32) (Page[int], error) {
+		return Page[int]{}, boom
+	}
+	for _, err := range Iterate(context.Background(), 10, fetch) {
+		require.ErrorIs(t, err, boom)
+	}
+}
+
+func TestCollect_Limit(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+	calls := 0
+	got, total, err := Collect(context.Background(), 2, 5, pagesOf(items, &calls))
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, got)
+	require.Equal(t, int64(7), total)
+	require.Equal(t, 3, calls)
+}
+
+func TestCollect_All(t *testing.T) {
+	items := []int{1, 2, 3, 4}
+	calls := 0
+	got, total, err := Collect(context.Background(), 2, 0, pagesOf(items, &calls))
+	require.NoError(t, err)
+	require.Equal(t, items, got)
+	require.Equal(t, int64(4), total)
+	// the second page is full, so a third (empty) request is needed to detect the end
+	require.Equal(t, 3, calls)
+}
+
+func TestSearchAll_BindsFilter(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	search := func(ctx context.Context, from int, size int32, after Cursor) (Page[int], error) {
+		var matching []int
+		for _, it := range items {
+			if it >= from {
+				matching = append(matching, it)
+			}
+		}
+		calls := 0
+		return pagesOf(matching, &calls)(ctx, after, size)
+	}
+	got, total, err := SearchAll(context.Background(), search, 3, 2, 0)
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 4, 5}, got)
+	require.Equal(t, int64(3), total)
+}
@@ -10,6 +10,7 @@ type API interface {
 	camunda.Base
 	GetProcessDefinitionByKey(ctx context.Context, key int64) (ProcessDefinition, error)
 	SearchProcessDefinitions(ctx context.Context, filter SearchFilterOpts, size int32) (ProcessDefinitions, error)
+	SearchProcessDefinitionsPage(ctx context.Context, filter SearchFilterOpts, size int32, after camunda.Cursor) (camunda.Page[ProcessDefinition], error)
 }
 
 type ProcessDefinition struct {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/grafvonb/camunder/pkg/camunda"
 )
@@ -15,7 +16,16 @@ type API interface {
 	camunda.Base
 	GetProcessInstanceByKey(ctx context.Context, key int64) (ProcessInstance, error)
 	SearchForProcessInstances(ctx context.Context, filter SearchFilterOpts, size int32) (ProcessInstances, error)
+	SearchForProcessInstancesPage(ctx context.Context, filter SearchFilterOpts, size int32, after camunda.Cursor) (camunda.Page[ProcessInstance], error)
+	CreateProcessInstance(ctx context.Context, req CreateRequest) (CreateResponse, error)
 	CancelProcessInstance(ctx context.Context, key int64) (CancelResponse, error)
+	MigrateProcessInstance(ctx context.Context, key int64, plan MigrationPlan) (MigrateResponse, error)
+	GetActiveElementsOfProcessInstance(ctx context.Context, key int64) ([]ElementInstance, error)
+	GetElementInstancesOfProcessInstance(ctx context.Context, key int64) ([]ElementInstance, error)
+	GetSequenceFlowsOfProcessInstance(ctx context.Context, key int64) ([]string, error)
+	GetElementStatisticsOfProcessInstance(ctx context.Context, key int64) ([]ElementStatistics, error)
+	ModifyProcessInstance(ctx context.Context, key int64, plan ModificationPlan) (ModifyResponse, error)
+	ModifyProcessInstances(ctx context.Context, filter SearchFilterOpts, moves []MoveInstruction) (BatchOperation, error)
 	GetDirectChildrenOfProcessInstance(ctx context.Context, key int64) (ProcessInstances, error)
 	FilterProcessInstanceWithOrphanParent(ctx context.Context, items []ProcessInstance) ([]ProcessInstance, error)
 	DeleteProcessInstance(ctx context.Context, key int64) (ChangeStatus, error)
@@ -32,6 +42,7 @@ type ProcessInstance struct {
 	ParentKey                 int64  `json:"parentKey,omitempty"`
 	ParentProcessInstanceKey  int64  `json:"parentProcessInstanceKey,omitempty"`
 	ProcessDefinitionKey      int64  `json:"processDefinitionKey,omitempty"`
+	ProcessDefinitionName     string `json:"processDefinitionName,omitempty"`
 	ProcessVersion            int32  `json:"processVersion,omitempty"`
 	ProcessVersionTag         string `json:"processVersionTag,omitempty"`
 	StartDate                 string `json:"startDate,omitempty"`
@@ -47,6 +58,46 @@ type ProcessInstances struct {
 // ProcessInstanceState defines model for ProcessInstance.State.
 type ProcessInstanceState string
 
+// CreateRequest starts a process instance of the latest or the given version of a process definition.
+// With AwaitCompletion the call returns when the instance has completed, including the FetchVariables
+// (all variables if empty), or fails after RequestTimeout (server default if zero).
+type CreateRequest struct {
+	BpmnProcessId   string
+	ProcessVersion  int32
+	Variables       map[string]any
+	AwaitCompletion bool
+	FetchVariables  []string
+	RequestTimeout  time.Duration
+}
+
+// CreateResponse is the created process instance; Variables are only set when the completion was awaited.
+type CreateResponse struct {
+	ProcessInstance
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// ElementInstance is an instance of a BPMN element (flow node) within a process instance.
+type ElementInstance struct {
+	Key         int64  `json:"key,omitempty"`
+	ElementId   string `json:"elementId,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Type        string `json:"type,omitempty"`
+	State       string `json:"state,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	EndDate     string `json:"endDate,omitempty"`
+	Incident    bool   `json:"incident,omitempty"`
+	IncidentKey int64  `json:"incidentKey,omitempty"`
+}
+
+// ElementStatistics counts the element instances of one element of a process instance by state.
+type ElementStatistics struct {
+	ElementId string `json:"elementId"`
+	Active    int64  `json:"active"`
+	Canceled  int64  `json:"canceled"`
+	Completed int64  `json:"completed"`
+	Incidents int64  `json:"incidents"`
+}
+
 type CancelResponse struct {
 	StatusCode int
 	Status     string
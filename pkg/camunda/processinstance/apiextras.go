@@ -4,10 +4,23 @@ import (
 	"context"
 )
 
+// AncestryStrategy tells how a Walker finds the ancestry of a process instance.
+type AncestryStrategy string
+
+const (
+	// AncestryByParentKey gets the instances one by one, following their parent keys up to the root.
+	AncestryByParentKey AncestryStrategy = "parent-key"
+	// AncestryByCallHierarchy gets the whole ancestry in one request. Its instances have only the
+	// keys, parent keys and the process definition key and name set.
+	AncestryByCallHierarchy AncestryStrategy = "call-hierarchy"
+)
+
 type Walker interface {
+	// AncestryStrategy returns how Ancestry finds the ancestors, and so which fields their instances have.
+	AncestryStrategy() AncestryStrategy
 	Ancestry(ctx context.Context, startKey int64) (rootKey int64, path []int64, chain map[int64]ProcessInstance, err error)
-	Descendants(ctx context.Context, rootKey int64) (desc []int64, edges map[int64][]int64, chain map[int64]ProcessInstance, err error)
-	Family(ctx context.Context, startKey int64) (fam []int64, edges map[int64][]int64, chain map[int64]ProcessInstance, err error)
+	Descendants(ctx context.Context, rootKey int64, opts ...WalkOption) (desc []int64, edges map[int64][]int64, chain map[int64]ProcessInstance, err error)
+	Family(ctx context.Context, startKey int64, opts ...WalkOption) (fam []int64, edges map[int64][]int64, chain map[int64]ProcessInstance, err error)
 }
 
 func AsWalker(api API) (Walker, bool) {
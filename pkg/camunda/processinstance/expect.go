@@ -0,0 +1,473 @@
+package processinstance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafvonb/camunder/pkg/camunda/variable"
+)
+
+// StateAbsent is expected of a process instance that is gone, e.g. deleted; it is no search filter.
+const StateAbsent State = "absent"
+
+var (
+	// ErrConditionFailed ends an expectation that can no longer be met, e.g. the instance completed
+	// while it was expected to be canceled.
+	ErrConditionFailed = errors.New("condition can no longer be met")
+	// ErrExpectTimeout ends an expectation that was not met before the deadline or the last poll.
+	ErrExpectTimeout = errors.New("timed out waiting for the condition")
+)
+
+// ParseExpectedState parses the state a process instance is expected to reach: active, completed,
+// canceled or absent.
+func ParseExpectedState(in string) (State, error) {
+	if strings.EqualFold(in, string(StateAbsent)) {
+		return StateAbsent, nil
+	}
+	s, err := ParseState(in)
+	if err != nil || s == StateAll {
+		return "", fmt.Errorf("%q is no expected state (valid: active, completed, canceled, absent)", in)
+	}
+	return s, nil
+}
+
+// Condition is what a process instance is expected to meet; all fields set must hold at once.
+type Condition struct {
+	State     State               // reach this state, StateAbsent for no longer found
+	Incident  *bool               // have an incident (true) or none (false)
+	ElementId string              // have entered this element (flow node)
+	Variables []VariablePredicate // have variables meeting all these predicates
+}
+
+func (c Condition) String() string {
+	var parts []string
+	if c.State != "" {
+		parts = append(parts, "state "+string(c.State))
+	}
+	switch {
+	case c.Incident == nil:
+	case *c.Incident:
+		parts = append(parts, "has incident")
+	default:
+		parts = append(parts, "no incident")
+	}
+	if c.ElementId != "" {
+		parts = append(parts, "element "+c.ElementId+" reached")
+	}
+	for _, p := range c.Variables {
+		parts = append(parts, "variable "+p.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Validate rejects conditions that cannot be met together.
+func (c Condition) Validate() error {
+	if c.State == StateAbsent && (c.Incident != nil || c.ElementId != "" || len(c.Variables) > 0) {
+		return errors.New("the absent state cannot be combined with other conditions")
+	}
+	if c.State == "" && c.Incident == nil && c.ElementId == "" && len(c.Variables) == 0 {
+		return errors.New("no condition given")
+	}
+	return nil
+}
+
+// Observation is what is known about a process instance to check a Condition. Elements and Variables
+// are only fetched if the condition needs them.
+type Observation struct {
+	Absent    bool
+	Instance  ProcessInstance
+	Elements  []ElementInstance
+	Variables map[string]any // by name, only those found
+}
+
+// Verdict is the outcome of checking a Condition.
+type Verdict int
+
+const (
+	Pending Verdict = iota // not met yet, but may still be
+	Met
+	Failed // the instance has ended without meeting it
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Met:
+		return "met"
+	case Failed:
+		return "failed"
+	default:
+		return "pending"
+	}
+}
+
+// Check returns whether the observed instance meets the condition and, unless it does, why not.
+// A condition an ended (completed or canceled) instance does not meet has failed; an instance not
+// found is pending, as it may not be visible yet.
+func (c Condition) Check(o Observation) (Verdict, string) {
+	if c.State == StateAbsent {
+		if o.Absent {
+			return Met, ""
+		}
+		return Pending, fmt.Sprintf("still exists in state %s", o.Instance.State)
+	}
+	if o.Absent {
+		return Pending, "not found"
+	}
+	pi := o.Instance
+	var unmet []string
+	ended := pi.State.EqualsIgnoreCase(StateCompleted) || pi.State.EqualsIgnoreCase(StateCanceled)
+	stateUnmet := c.State != "" && !pi.State.EqualsIgnoreCase(c.State)
+	if stateUnmet && !ended {
+		unmet = append(unmet, fmt.Sprintf("state is %s", pi.State))
+	}
+	if c.Incident != nil && pi.Incident != *c.Incident {
+		if pi.Incident {
+			unmet = append(unmet, "has an incident")
+		} else {
+			unmet = append(unmet, "has no incident")
+		}
+	}
+	if c.ElementId != "" && !hasElement(o.Elements, c.ElementId) {
+		unmet = append(unmet, fmt.Sprintf("element %s not reached", c.ElementId))
+	}
+	for _, p := range c.Variables {
+		if !p.Holds(o.Variables) {
+			unmet = append(unmet, fmt.Sprintf("variable %s does not hold", p))
+		}
+	}
+	switch {
+	case len(unmet) == 0 && !stateUnmet:
+		return Met, ""
+	case ended && len(unmet) == 0:
+		return Failed, fmt.Sprintf("ended in state %s", pi.State)
+	case ended:
+		return Failed, fmt.Sprintf("ended in state %s: %s", pi.State, strings.Join(unmet, ", "))
+	}
+	return Pending, strings.Join(unmet, ", ")
+}
+
+func hasElement(elements []ElementInstance, id string) bool {
+	for _, e := range elements {
+		if e.ElementId == id {
+			return true
+		}
+	}
+	return false
+}
+
+// VariablePredicate compares a process variable with a value, e.g. amount>=100 or approved=true.
+// Without an operator, the variable just has to exist.
+type VariablePredicate struct {
+	Name  string
+	Op    string // =, !=, >, >=, <, <= or empty
+	Value any    // the JSON value compared with, strings may be given without quotes
+}
+
+// variableOps are tried in this order, so that >= is not taken for >.
+var variableOps = []string{"!=", ">=", "<=", "==", "=", ">", "<"}
+
+// ParseVariablePredicate parses name, name=value, name!=value, name>value, name>=value, name<value
+// or name<=value. The value is JSON, anything else is taken as string.
+func ParseVariablePredicate(s string) (VariablePredicate, error) {
+	i, op := -1, ""
+	for _, o := range variableOps {
+		if j := strings.Index(s, o); j >= 0 && (i < 0 || j < i) {
+			i, op = j, o
+		}
+	}
+	if i < 0 {
+		name := strings.TrimSpace(s)
+		if name == "" {
+			return VariablePredicate{}, errors.New("variable predicate without name")
+		}
+		return VariablePredicate{Name: name}, nil
+	}
+	name, raw := strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+len(op):])
+	if name == "" {
+		return VariablePredicate{}, fmt.Errorf("variable predicate %q without name", s)
+	}
+	if op == "==" {
+		op = "="
+	}
+	v := variable.DecodeValue(raw)
+	if (op == ">" || op == ">=" || op == "<" || op == "<=") && !isOrdered(v) {
+		return VariablePredicate{}, fmt.Errorf("variable predicate %q: %s needs a number or string", s, op)
+	}
+	return VariablePredicate{Name: name, Op: op, Value: v}, nil
+}
+
+func (p VariablePredicate) String() string {
+	if p.Op == "" {
+		return p.Name + " exists"
+	}
+	b, _ := json.Marshal(p.Value)
+	return p.Name + p.Op + string(b)
+}
+
+// Holds reports whether the variables, decoded from JSON, meet the predicate. Numbers are compared
+// by value, whether decoded as json.Number or float64.
+func (p VariablePredicate) Holds(vars map[string]any) bool {
+	v, ok := vars[p.Name]
+	if !ok {
+		return false
+	}
+	switch p.Op {
+	case "":
+		return true
+	case "=":
+		return equal(v, p.Value)
+	case "!=":
+		return !equal(v, p.Value)
+	}
+	c, ok := compare(v, p.Value)
+	if !ok {
+		return false
+	}
+	switch p.Op {
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	default:
+		return c <= 0
+	}
+}
+
+func isOrdered(v any) bool {
+	switch v.(type) {
+	case json.Number, float64, string:
+		return true
+	}
+	return false
+}
+
+// equal compares two JSON values, numbers by value.
+func equal(a, b any) bool {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x.Cmp(y) == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compare orders two numbers or two strings.
+func compare(a, b any) (int, bool) {
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			return x.Cmp(y), true
+		}
+		return 0, false
+	}
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+	return 0, false
+}
+
+// number returns the exact value of a json.Number or float64, so that large integers are not rounded.
+func number(v any) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(n.String())
+	case float64:
+		r := new(big.Rat)
+		if r.SetFloat64(n) == nil {
+			return nil, false
+		}
+		return r, true
+	}
+	return nil, false
+}
+
+// VariableGetter returns the value of a variable of a process instance, decoded from JSON, or
+// found false if the instance has no variable of that name.
+type VariableGetter func(ctx context.Context, key int64, name string) (value any, found bool, err error)
+
+// Observe fetches what is needed to check cond for the process instance. An instance not found
+// (ErrNotFound) is observed as absent.
+func Observe(ctx context.Context, api API, vars VariableGetter, key int64, cond Condition) (Observation, error) {
+	pi, err := api.GetProcessInstanceByKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Observation{Absent: true}, nil
+		}
+		return Observation{}, fmt.Errorf("get %d: %w", key, err)
+	}
+	o := Observation{Instance: pi}
+	if cond.State == StateAbsent {
+		return o, nil
+	}
+	if cond.ElementId != "" {
+		if o.Elements, err = api.GetElementInstancesOfProcessInstance(ctx, key); err != nil {
+			return Observation{}, fmt.Errorf("get element instances of %d: %w", key, err)
+		}
+	}
+	for _, p := range cond.Variables {
+		if o.Variables == nil {
+			o.Variables = make(map[string]any)
+		}
+		v, found, err := vars(ctx, key, p.Name)
+		if err != nil {
+			return Observation{}, fmt.Errorf("get variable %s of %d: %w", p.Name, key, err)
+		}
+		if found {
+			o.Variables[p.Name] = v
+		}
+	}
+	return o, nil
+}
+
+// ExpectResult is the last verdict on one process instance of an expectation.
+type ExpectResult struct {
+	Key     int64
+	Verdict Verdict
+	Reason  string // why the condition is not met, empty if it is
+	Err     error  // the last observation failed
+}
+
+// ExpectOpts control how often and how long the process instances are polled.
+type ExpectOpts struct {
+	Any       bool                              // one instance meeting the condition suffices, not all
+	Parallel  int                               // instances observed at once, 0 for DefaultWalkParallel
+	Delay     time.Duration                     // between the first polls
+	NextDelay func(time.Duration) time.Duration // delay after the given one, nil keeps Delay
+	MaxPolls  int                               // polls before ErrExpectTimeout, 0 for no limit
+	OnPoll    func(results []ExpectResult)      // called after each poll
+}
+
+type ExpectOption func(*ExpectOpts)
+
+// WithExpectAny ends the expectation once any instance meets the condition.
+func WithExpectAny() ExpectOption {
+	return func(o *ExpectOpts) { o.Any = true }
+}
+
+func WithExpectParallel(n int) ExpectOption {
+	return func(o *ExpectOpts) { o.Parallel = n }
+}
+
+// WithPollDelay sets the delay before the second poll and how it grows after that, e.g. by backoff.
+func WithPollDelay(delay time.Duration, next func(time.Duration) time.Duration) ExpectOption {
+	return func(o *ExpectOpts) { o.Delay, o.NextDelay = delay, next }
+}
+
+func WithMaxPolls(n int) ExpectOption {
+	return func(o *ExpectOpts) { o.MaxPolls = n }
+}
+
+func WithOnPoll(fn func(results []ExpectResult)) ExpectOption {
+	return func(o *ExpectOpts) { o.OnPoll = fn }
+}
+
+// Expect polls the process instances until all of them, or with WithExpectAny one, meet cond.
+// It returns ErrConditionFailed as soon as that can no longer happen and ErrExpectTimeout when ctx
+// expires or the polls are used up; the results hold the last verdict on each instance. Instances
+// that met the condition, or failed it, are not polled again.
+func Expect(ctx context.Context, observe func(ctx context.Context, key int64) (Observation, error), keys []int64, cond Condition, opts ...ExpectOption) ([]ExpectResult, error) {
+	o := ExpectOpts{Parallel: DefaultWalkParallel, Delay: time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Parallel <= 0 {
+		o.Parallel = DefaultWalkParallel
+	}
+	if o.Delay <= 0 {
+		o.Delay = time.Second
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no process instances to expect")
+	}
+	results := make([]ExpectResult, len(keys))
+	for i, k := range keys {
+		results[i] = ExpectResult{Key: k}
+	}
+
+	delay := o.Delay
+	for polls := 1; ; polls++ {
+		poll(ctx, results, o.Parallel, func(r *ExpectResult) {
+			obs, err := observe(ctx, r.Key)
+			if err != nil {
+				r.Err = err
+				return
+			}
+			r.Verdict, r.Reason = cond.Check(obs)
+			r.Err = nil
+		})
+		if err := ctx.Err(); err != nil {
+			return results, expectCtxErr(err, cond)
+		}
+		if o.OnPoll != nil {
+			o.OnPoll(results)
+		}
+		met, failed := 0, 0
+		for _, r := range results {
+			switch r.Verdict {
+			case Met:
+				met++
+			case Failed:
+				failed++
+			}
+		}
+		switch {
+		case o.Any && met > 0, !o.Any && met == len(results):
+			return results, nil
+		case !o.Any && failed > 0, o.Any && failed == len(results):
+			return results, fmt.Errorf("%w: %s", ErrConditionFailed, cond)
+		case o.MaxPolls > 0 && polls >= o.MaxPolls:
+			return results, fmt.Errorf("%w after %d polls: %s", ErrExpectTimeout, polls, cond)
+		}
+
+		select {
+		case <-time.After(delay):
+			if o.NextDelay != nil {
+				delay = o.NextDelay(delay)
+			}
+		case <-ctx.Done():
+			return results, expectCtxErr(ctx.Err(), cond)
+		}
+	}
+}
+
+// poll observes the pending instances, up to parallel at once.
+func poll(ctx context.Context, results []ExpectResult, parallel int, observe func(r *ExpectResult)) {
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i := range results {
+		if results[i].Verdict != Pending {
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func(r *ExpectResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			observe(r)
+		}(&results[i])
+	}
+	wg.Wait()
+}
+
+func expectCtxErr(err error, cond Condition) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w (%w): %s", ErrExpectTimeout, err, cond)
+	}
+	return err
+}
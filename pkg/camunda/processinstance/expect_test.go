@@ -0,0 +1,248 @@
+package processinstance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpectedState(t *testing.T) {
+	s, err := ParseExpectedState("Absent")
+	require.NoError(t, err)
+	require.Equal(t, StateAbsent, s)
+	s, err = ParseExpectedState("completed")
+	require.NoError(t, err)
+	require.Equal(t, StateCompleted, s)
+
+	for _, invalid := range []string{"all", "done", ""} {
+		_, err = ParseExpectedState(invalid)
+		require.Error(t, err, invalid)
+	}
+	_, err = ParseState("absent")
+	require.Error(t, err, "absent is no search filter")
+}
+
+func TestVariablePredicate(t *testing.T) {
+	vars := map[string]any{"approved": true, "amount": float64(120), "status": "shipped", "order": map[string]any{"id": "A-1"},
+		"orderKey": json.Number("2251799813685251"), "price": json.Number("9.90")}
+	cases := map[string]bool{
+		"approved":             true,
+		"missing":              false,
+		"approved=true":        true,
+		"approved==false":      false,
+		"amount>=120":          true,
+		"amount>120":           false,
+		"amount<1e3":           true,
+		"amount!=120":          false,
+		"status=shipped":       true,
+		`status="shipped"`:     true,
+		"status<t":             true,
+		"status>5":             false,
+		`order={"id":"A-1"}`:   true,
+		`order!={"id":"A-2"}`:  true,
+		"missing!=1":           false,
+		"note = a=b":           false,
+		"amount >= 100":        true,
+		"approved=\"true\"":    false,
+		"status!=\"canceled\"": true,
+		// numbers are compared by value and without rounding
+		"orderKey=2251799813685251":     true,
+		"orderKey=2251799813685250":     false,
+		"orderKey>2251799813685250":     true,
+		"price=9.9":                     true,
+		"amount=1.2e2":                  true,
+		"orderKey=\"2251799813685251\"": false,
+	}
+	for expr, want := range cases {
+		p, err := ParseVariablePredicate(expr)
+		require.NoError(t, err, expr)
+		require.Equal(t, want, p.Holds(vars), expr)
+	}
+
+	p, err := ParseVariablePredicate("note = a=b")
+	require.NoError(t, err)
+	require.Equal(t, VariablePredicate{Name: "note", Op: "=", Value: "a=b"}, p)
+
+	for _, invalid := range []string{"", "=1", "amount>true", `amount<{"a":1}`} {
+		_, err = ParseVariablePredicate(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestCondition_Check(t *testing.T) {
+	yes := true
+	active := ProcessInstance{Key: 1, State: StateActive}
+	completed := ProcessInstance{Key: 1, State: "COMPLETED"}
+	withIncident := ProcessInstance{Key: 1, State: StateActive, Incident: true}
+
+	cases := []struct {
+		name string
+		cond Condition
+		obs  Observation
+		want Verdict
+	}{
+		{"state reached", Condition{State: StateCompleted}, Observation{Instance: completed}, Met},
+		{"state pending", Condition{State: StateCompleted}, Observation{Instance: active}, Pending},
+		{"ended in another state", Condition{State: StateCanceled}, Observation{Instance: completed}, Failed},
+		{"not found yet", Condition{State: StateActive}, Observation{Absent: true}, Pending},
+		{"absent", Condition{State: StateAbsent}, Observation{Absent: true}, Met},
+		{"not absent yet", Condition{State: StateAbsent}, Observation{Instance: completed}, Pending},
+		{"has incident", Condition{Incident: &yes}, Observation{Instance: withIncident}, Met},
+		{"ended without incident", Condition{Incident: &yes}, Observation{Instance: completed}, Failed},
+		{"element reached", Condition{ElementId: "ship"}, Observation{Instance: active, Elements: []ElementInstance{{ElementId: "start"}, {ElementId: "ship"}}}, Met},
+		{"element not reached yet", Condition{ElementId: "ship"}, Observation{Instance: active, Elements: []ElementInstance{{ElementId: "start"}}}, Pending},
+		{"ended without element", Condition{ElementId: "ship"}, Observation{Instance: completed}, Failed},
+		{"variable holds", Condition{Variables: []VariablePredicate{{Name: "ok", Op: "=", Value: true}}}, Observation{Instance: active, Variables: map[string]any{"ok": true}}, Met},
+		{"all must hold", Condition{State: StateCompleted, Variables: []VariablePredicate{{Name: "ok"}}}, Observation{Instance: completed}, Failed},
+	}
+	for _, c := range cases {
+		got, reason := c.cond.Check(c.obs)
+		require.Equal(t, c.want, got, c.name)
+		require.Equal(t, got == Met, reason == "", c.name)
+	}
+
+	_, reason := Condition{State: StateCanceled, ElementId: "ship"}.Check(Observation{Instance: completed})
+	require.Equal(t, "ended in state COMPLETED: element ship not reached", reason)
+}
+
+func TestCondition_Validate(t *testing.T) {
+	yes := true
+	require.NoError(t, Condition{State: StateCompleted, Incident: &yes}.Validate())
+	require.Error(t, Condition{}.Validate())
+	require.Error(t, Condition{State: StateAbsent, ElementId: "ship"}.Validate())
+}
+
+// observations serves the verdicts of the process instances by poll, the last one repeating.
+type observations struct {
+	mu    sync.Mutex
+	polls map[int64]int
+	seq   map[int64][]ProcessInstance
+}
+
+func (o *observations) observe(_ context.Context, key int64) (Observation, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	seq := o.seq[key]
+	if seq == nil {
+		return Observation{}, errors.New("unavailable")
+	}
+	i := min(o.polls[key], len(seq)-1)
+	o.polls[key]++
+	return Observation{Instance: seq[i]}, nil
+}
+
+func newObservations(seq map[int64][]ProcessInstance) *observations {
+	return &observations{polls: make(map[int64]int), seq: seq}
+}
+
+func TestExpect(t *testing.T) {
+	active, completed, canceled := ProcessInstance{State: StateActive}, ProcessInstance{State: StateCompleted}, ProcessInstance{State: StateCanceled}
+	cond := Condition{State: StateCompleted}
+	fast := WithPollDelay(time.Millisecond, nil)
+
+	t.Run("all", func(t *testing.T) {
+		o := newObservations(map[int64][]ProcessInstance{1: {completed}, 2: {active, active, completed}})
+		results, err := Expect(t.Context(), o.observe, []int64{1, 2}, cond, fast)
+		require.NoError(t, err)
+		require.Equal(t, []ExpectResult{{Key: 1, Verdict: Met}, {Key: 2, Verdict: Met}}, results)
+		require.Equal(t, 1, o.polls[1], "met instances are not polled again")
+		require.Equal(t, 3, o.polls[2])
+	})
+
+	t.Run("all fails with one", func(t *testing.T) {
+		o := newObservations(map[int64][]ProcessInstance{1: {active}, 2: {active, canceled}})
+		results, err := Expect(t.Context(), o.observe, []int64{1, 2}, cond, fast)
+		require.ErrorIs(t, err, ErrConditionFailed)
+		require.Equal(t, Pending, results[0].Verdict)
+		require.Equal(t, Failed, results[1].Verdict)
+		require.Equal(t, "ended in state canceled", results[1].Reason)
+	})
+
+	t.Run("any", func(t *testing.T) {
+		o := newObservations(map[int64][]ProcessInstance{1: {canceled}, 2: {active, completed}, 3: {active}})
+		results, err := Expect(t.Context(), o.observe, []int64{1, 2, 3}, cond, fast, WithExpectAny())
+		require.NoError(t, err)
+		require.Equal(t, Met, results[1].Verdict)
+	})
+
+	t.Run("any fails with all", func(t *testing.T) {
+		o := newObservations(map[int64][]ProcessInstance{1: {canceled}, 2: {active, canceled}})
+		_, err := Expect(t.Context(), o.observe, []int64{1, 2}, cond, fast, WithExpectAny())
+		require.ErrorIs(t, err, ErrConditionFailed)
+	})
+
+	t.Run("max polls", func(t *testing.T) {
+		o := newObservations(map[int64][]ProcessInstance{1: {active}, 2: nil})
+		var polls int
+		results, err := Expect(t.Context(), o.observe, []int64{1, 2}, cond, fast, WithMaxPolls(3),
+			WithOnPoll(func([]ExpectResult) { polls++ }))
+		require.ErrorIs(t, err, ErrExpectTimeout)
+		require.Equal(t, 3, polls)
+		require.EqualError(t, results[1].Err, "unavailable", "failed observations are retried")
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+		o := newObservations(map[int64][]ProcessInstance{1: {active}})
+		_, err := Expect(ctx, o.observe, []int64{1}, cond, fast)
+		require.ErrorIs(t, err, ErrExpectTimeout)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		o := newObservations(map[int64][]ProcessInstance{1: {active}})
+		_, err := Expect(ctx, o.observe, []int64{1}, cond, fast)
+		require.ErrorIs(t, err, context.Canceled)
+		require.NotErrorIs(t, err, ErrExpectTimeout)
+	})
+}
+
+// observeAPI serves one process instance with its element instances.
+type observeAPI struct {
+	API
+	pi       *ProcessInstance
+	elements []ElementInstance
+}
+
+func (a *observeAPI) GetProcessInstanceByKey(_ context.Context, key int64) (ProcessInstance, error) {
+	if a.pi == nil {
+		return ProcessInstance{}, fmt.Errorf("process instance with key %d: %w", key, ErrNotFound)
+	}
+	return *a.pi, nil
+}
+
+func (a *observeAPI) GetElementInstancesOfProcessInstance(_ context.Context, key int64) ([]ElementInstance, error) {
+	return a.elements, nil
+}
+
+func TestObserve(t *testing.T) {
+	o, err := Observe(t.Context(), &observeAPI{}, nil, 1, Condition{State: StateAbsent})
+	require.NoError(t, err)
+	require.True(t, o.Absent)
+
+	api := &observeAPI{pi: &ProcessInstance{Key: 1, State: StateActive}, elements: []ElementInstance{{ElementId: "ship"}}}
+	vars := func(_ context.Context, key int64, name string) (any, bool, error) {
+		if name == "amount" {
+			return float64(120), true, nil
+		}
+		return nil, false, nil
+	}
+	cond := Condition{ElementId: "ship", Variables: []VariablePredicate{{Name: "amount"}, {Name: "missing"}}}
+	o, err = Observe(t.Context(), api, vars, 1, cond)
+	require.NoError(t, err)
+	require.Len(t, o.Elements, 1)
+	require.Equal(t, map[string]any{"amount": float64(120)}, o.Variables)
+
+	o, err = Observe(t.Context(), api, nil, 1, Condition{State: StateCompleted})
+	require.NoError(t, err)
+	require.Nil(t, o.Elements, "only fetched if needed")
+}
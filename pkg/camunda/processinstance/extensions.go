@@ -36,6 +36,7 @@ func (r ProcessInstances) filterByBool(predicate func(*ProcessInstance) bool, wa
 			items = append(items[:i], items[i+1:]...)
 		}
 	}
+	r.Items = items
 	r.Total = int32(len(r.Items))
 	return r
 }
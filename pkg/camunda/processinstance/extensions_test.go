@@ -0,0 +1,26 @@
+package processinstance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterByHavingIncidents(t *testing.T) {
+	r := ProcessInstances{Total: 4, Items: []ProcessInstance{
+		{Key: 1, Incident: true},
+		{Key: 2},
+		{Key: 3, Incident: true},
+		{Key: 4},
+	}}
+	got := r.FilterByHavingIncidents(true)
+	require.Equal(t, int32(2), got.Total)
+	require.Equal(t, []ProcessInstance{{Key: 1, Incident: true}, {Key: 3, Incident: true}}, got.Items)
+}
+
+func TestFilterChildrenOnly(t *testing.T) {
+	r := ProcessInstances{Items: []ProcessInstance{{Key: 1}, {Key: 2, ParentKey: 1}, {Key: 3}}}
+	got := r.FilterChildrenOnly()
+	require.Equal(t, int32(1), got.Total)
+	require.Equal(t, []ProcessInstance{{Key: 2, ParentKey: 1}}, got.Items)
+}
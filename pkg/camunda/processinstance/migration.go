@@ -0,0 +1,78 @@
+package processinstance
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// elementTypeProcess is the type of the element instance of the process itself, which needs no mapping.
+const elementTypeProcess = "PROCESS"
+
+// MigrationPlan moves process instances to the target process definition, mapping each active element
+// of the source definition to an element of the target. It can be read from YAML:
+//
+//	targetProcessDefinitionKey: 2251799813685249
+//	mappingInstructions:
+//	  - sourceElementId: review
+//	    targetElementId: review_v2
+type MigrationPlan struct {
+	TargetProcessDefinitionKey int64                `json:"targetProcessDefinitionKey" yaml:"targetProcessDefinitionKey"`
+	MappingInstructions        []MappingInstruction `json:"mappingInstructions" yaml:"mappingInstructions"`
+}
+
+type MappingInstruction struct {
+	SourceElementId string `json:"sourceElementId" yaml:"sourceElementId"`
+	TargetElementId string `json:"targetElementId" yaml:"targetElementId"`
+}
+
+type MigrateResponse struct {
+	StatusCode int
+	Status     string
+}
+
+// ParseMapping parses a mapping instruction given as source:target.
+func ParseMapping(in string) (MappingInstruction, error) {
+	src, tgt, ok := strings.Cut(in, ":")
+	if !ok || src == "" || tgt == "" {
+		return MappingInstruction{}, fmt.Errorf("invalid mapping %q (expected source:target)", in)
+	}
+	return MappingInstruction{SourceElementId: src, TargetElementId: tgt}, nil
+}
+
+// Validate checks that the plan has a target and no element is mapped twice.
+func (p MigrationPlan) Validate() error {
+	var errs []error
+	if p.TargetProcessDefinitionKey <= 0 {
+		errs = append(errs, errors.New("target process definition key is missing"))
+	}
+	if len(p.MappingInstructions) == 0 {
+		errs = append(errs, errors.New("no mapping instructions"))
+	}
+	seen := make(map[string]bool, len(p.MappingInstructions))
+	for _, m := range p.MappingInstructions {
+		if seen[m.SourceElementId] {
+			errs = append(errs, fmt.Errorf("source element %q is mapped more than once", m.SourceElementId))
+		}
+		seen[m.SourceElementId] = true
+	}
+	return errors.Join(errs...)
+}
+
+// MissingMappings returns the sorted IDs of the active elements that have no mapping instruction.
+func (p MigrationPlan) MissingMappings(active []ElementInstance) []string {
+	mapped := make(map[string]bool, len(p.MappingInstructions))
+	for _, m := range p.MappingInstructions {
+		mapped[m.SourceElementId] = true
+	}
+	var missing []string
+	for _, e := range active {
+		if strings.EqualFold(e.Type, elementTypeProcess) || mapped[e.ElementId] || slices.Contains(missing, e.ElementId) {
+			continue
+		}
+		missing = append(missing, e.ElementId)
+	}
+	slices.Sort(missing)
+	return missing
+}
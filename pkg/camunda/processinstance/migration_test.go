@@ -0,0 +1,44 @@
+package processinstance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMapping(t *testing.T) {
+	got, err := ParseMapping("review:review_v2")
+	require.NoError(t, err)
+	require.Equal(t, MappingInstruction{SourceElementId: "review", TargetElementId: "review_v2"}, got)
+
+	for _, in := range []string{"review", ":b", "a:", ""} {
+		_, err = ParseMapping(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestMigrationPlanValidate(t *testing.T) {
+	require.Error(t, MigrationPlan{}.Validate())
+	require.Error(t, MigrationPlan{TargetProcessDefinitionKey: 1, MappingInstructions: []MappingInstruction{
+		{SourceElementId: "a", TargetElementId: "b"},
+		{SourceElementId: "a", TargetElementId: "c"},
+	}}.Validate())
+	require.NoError(t, MigrationPlan{TargetProcessDefinitionKey: 1, MappingInstructions: []MappingInstruction{
+		{SourceElementId: "a", TargetElementId: "b"},
+	}}.Validate())
+}
+
+func TestMissingMappings(t *testing.T) {
+	plan := MigrationPlan{TargetProcessDefinitionKey: 1, MappingInstructions: []MappingInstruction{
+		{SourceElementId: "review", TargetElementId: "review_v2"},
+	}}
+	active := []ElementInstance{
+		{ElementId: "order", Type: "PROCESS"},
+		{ElementId: "review", Type: "USER_TASK"},
+		{ElementId: "ship", Type: "SERVICE_TASK"},
+		{ElementId: "approve", Type: "USER_TASK"},
+		{ElementId: "ship", Type: "SERVICE_TASK"},
+	}
+	require.Equal(t, []string{"approve", "ship"}, plan.MissingMappings(active))
+	require.Empty(t, plan.MissingMappings(active[:2]))
+}
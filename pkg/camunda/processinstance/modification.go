@@ -0,0 +1,98 @@
+package processinstance
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ModificationPlan changes the active elements of a process instance: it activates elements, terminates
+// element instances and can create variables together with the activation.
+// Variables are created in the global scope of the process instance.
+type ModificationPlan struct {
+	Activate  []ActivateInstruction
+	Terminate []int64 // element instance keys
+	Variables map[string]any
+}
+
+// ActivateInstruction activates the element, optionally within the given ancestor element instance.
+type ActivateInstruction struct {
+	ElementId                  string
+	AncestorElementInstanceKey int64 // zero lets the engine choose the flow scope
+}
+
+// MoveInstruction terminates all active instances of the source element and activates the target element
+// for each of them.
+type MoveInstruction struct {
+	SourceElementId string
+	TargetElementId string
+}
+
+type ModifyResponse struct {
+	StatusCode int
+	Status     string
+}
+
+// BatchOperation is a server-side operation over many process instances.
+type BatchOperation struct {
+	Key  string
+	Type string
+}
+
+// ParseActivate parses an activate instruction given as elementId[:ancestorElementInstanceKey].
+func ParseActivate(in string) (ActivateInstruction, error) {
+	id, ancestor, hasAncestor := strings.Cut(in, ":")
+	if id == "" {
+		return ActivateInstruction{}, fmt.Errorf("invalid activate instruction %q (expected elementId[:ancestorKey])", in)
+	}
+	a := ActivateInstruction{ElementId: id}
+	if hasAncestor {
+		k, err := strconv.ParseInt(ancestor, 10, 64)
+		if err != nil || k <= 0 {
+			return ActivateInstruction{}, fmt.Errorf("invalid ancestor key in activate instruction %q", in)
+		}
+		a.AncestorElementInstanceKey = k
+	}
+	return a, nil
+}
+
+// ParseMove parses a move instruction given as source:target.
+func ParseMove(in string) (MoveInstruction, error) {
+	src, tgt, ok := strings.Cut(in, ":")
+	if !ok || src == "" || tgt == "" {
+		return MoveInstruction{}, fmt.Errorf("invalid move instruction %q (expected source:target)", in)
+	}
+	return MoveInstruction{SourceElementId: src, TargetElementId: tgt}, nil
+}
+
+// Validate checks that the plan changes anything and that variables come with an activation.
+func (p ModificationPlan) Validate() error {
+	if len(p.Activate) == 0 && len(p.Terminate) == 0 {
+		return errors.New("no activate or terminate instructions")
+	}
+	if len(p.Variables) > 0 && len(p.Activate) == 0 {
+		return errors.New("variables can only be set together with an activate instruction")
+	}
+	return nil
+}
+
+// WithMoves returns a copy of the plan extended by the moves, resolved against the active elements
+// of one process instance. A move whose source element is not active changes nothing.
+func (p ModificationPlan) WithMoves(moves []MoveInstruction, active []ElementInstance) ModificationPlan {
+	out := ModificationPlan{
+		Activate:  append([]ActivateInstruction(nil), p.Activate...),
+		Terminate: append([]int64(nil), p.Terminate...),
+		Variables: p.Variables,
+	}
+	for _, m := range moves {
+		for _, e := range active {
+			if e.ElementId != m.SourceElementId {
+				continue
+			}
+			out.Terminate = append(out.Terminate, e.Key)
+			out.Activate = append(out.Activate, ActivateInstruction{ElementId: m.TargetElementId})
+		}
+	}
+	return out
+}
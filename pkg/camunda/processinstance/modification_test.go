@@ -0,0 +1,38 @@
+package processinstance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseActivate(t *testing.T) {
+	got, err := ParseActivate("retry_payment")
+	require.NoError(t, err)
+	require.Equal(t, ActivateInstruction{ElementId: "retry_payment"}, got)
+
+	got, err = ParseActivate("retry_payment:2251799813685260")
+	require.NoError(t, err)
+	require.Equal(t, ActivateInstruction{ElementId: "retry_payment", AncestorElementInstanceKey: 2251799813685260}, got)
+
+	for _, in := range []string{"", ":1", "a:", "a:x", "a:-1"} {
+		_, err = ParseActivate(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestModificationPlanWithMoves(t *testing.T) {
+	active := []ElementInstance{
+		{Key: 10, ElementId: "order", Type: "PROCESS"},
+		{Key: 11, ElementId: "pay", Type: "SERVICE_TASK"},
+		{Key: 12, ElementId: "pay", Type: "SERVICE_TASK"},
+		{Key: 13, ElementId: "ship", Type: "SERVICE_TASK"},
+	}
+	base := ModificationPlan{Terminate: []int64{13}}
+	got := base.WithMoves([]MoveInstruction{{SourceElementId: "pay", TargetElementId: "pay_v2"}}, active)
+	require.Equal(t, []int64{13, 11, 12}, got.Terminate)
+	require.Equal(t, []ActivateInstruction{{ElementId: "pay_v2"}, {ElementId: "pay_v2"}}, got.Activate)
+	require.Equal(t, []int64{13}, base.Terminate)
+
+	require.Error(t, ModificationPlan{}.WithMoves([]MoveInstruction{{SourceElementId: "gone", TargetElementId: "x"}}, active).Validate())
+}
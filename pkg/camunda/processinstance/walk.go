@@ -0,0 +1,148 @@
+package processinstance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/grafvonb/camunder/pkg/camunda"
+)
+
+// DefaultWalkParallel is the number of children searches a walk runs at once by default.
+const DefaultWalkParallel = 8
+
+// ErrMaxNodesReached ends a walk that found more process instances than allowed by WithMaxNodes.
+var ErrMaxNodesReached = errors.New("maximum number of process instances reached")
+
+// WalkOpts bound the traversal of the descendants of a process instance.
+type WalkOpts struct {
+	Parallel int                                 // children searches at once, 0 for DefaultWalkParallel
+	MaxDepth int                                 // levels below the root to expand, 0 for no limit
+	MaxNodes int                                 // process instances to collect, 0 for no limit
+	OnFound  func(pi ProcessInstance, depth int) // called for each process instance as it is found
+}
+
+type WalkOption func(*WalkOpts)
+
+func WithWalkParallel(n int) WalkOption {
+	return func(o *WalkOpts) { o.Parallel = n }
+}
+
+func WithMaxDepth(n int) WalkOption {
+	return func(o *WalkOpts) { o.MaxDepth = n }
+}
+
+func WithMaxNodes(n int) WalkOption {
+	return func(o *WalkOpts) { o.MaxNodes = n }
+}
+
+// WithOnFound streams the walk: fn is called for each process instance once it is found, the root first.
+// The calls are not concurrent, fn needs no locking.
+func WithOnFound(fn func(pi ProcessInstance, depth int)) WalkOption {
+	return func(o *WalkOpts) { o.OnFound = fn }
+}
+
+// WalkDescendants finds the root and all its descendants by a breadth-first search, which searches
+// the children of up to Parallel process instances at once. desc lists the keys in the order found,
+// edges maps each key to its children, also leaves with no children, and chain holds the instances.
+// With ErrMaxNodesReached, the instances found until then are returned along with the error.
+func WalkDescendants(ctx context.Context, api API, rootKey int64, opts ...WalkOption) (desc []int64, edges map[int64][]int64, chain map[int64]ProcessInstance, err error) {
+	o := WalkOpts{Parallel: DefaultWalkParallel}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Parallel <= 0 {
+		o.Parallel = DefaultWalkParallel
+	}
+
+	root, err := api.GetProcessInstanceByKey(ctx, rootKey)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("get %d: %w", rootKey, err)
+	}
+	edges = make(map[int64][]int64)
+	chain = make(map[int64]ProcessInstance)
+	found := func(pi ProcessInstance, depth int) {
+		desc = append(desc, pi.Key)
+		chain[pi.Key] = pi
+		edges[pi.Key] = nil
+		if o.OnFound != nil {
+			o.OnFound(pi, depth)
+		}
+	}
+	found(root, 0)
+
+	type node struct {
+		key   int64
+		depth int
+	}
+	type result struct {
+		node
+		children []ProcessInstance
+		err      error
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	jobs := make(chan node)
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for range o.Parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range jobs {
+				children, err := api.GetDirectChildrenOfProcessInstance(ctx, n.key)
+				select {
+				case results <- result{node: n, children: children.Items, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	defer func() {
+		cancel() // releases the workers still searching, after an error
+		close(jobs)
+		wg.Wait()
+	}()
+
+	// the maps are only touched here, the workers just search
+	var queue []node
+	expand := func(n node) {
+		if o.MaxDepth == 0 || n.depth < o.MaxDepth {
+			queue = append(queue, n)
+		}
+	}
+	expand(node{key: rootKey})
+	inFlight := 0
+	for len(queue) > 0 || inFlight > 0 {
+		var send chan node
+		var next node
+		if len(queue) > 0 {
+			send, next = jobs, queue[0]
+		}
+		select {
+		case send <- next:
+			queue = queue[1:]
+			inFlight++
+		case r := <-results:
+			inFlight--
+			if r.err != nil {
+				return nil, nil, nil, fmt.Errorf("list children of %d: %w", r.key, r.err)
+			}
+			for _, child := range r.children {
+				if _, seen := chain[child.Key]; seen {
+					return nil, nil, nil, fmt.Errorf("%w for this key %d", camunda.ErrCycleDetected, child.Key)
+				}
+				if o.MaxNodes > 0 && len(desc) >= o.MaxNodes {
+					return desc, edges, chain, fmt.Errorf("%w (%d)", ErrMaxNodesReached, o.MaxNodes)
+				}
+				edges[r.key] = append(edges[r.key], child.Key)
+				found(child, r.depth+1)
+				expand(node{key: child.Key, depth: r.depth + 1})
+			}
+		case <-ctx.Done():
+			return nil, nil, nil, ctx.Err()
+		}
+	}
+	return desc, edges, chain, nil
+}
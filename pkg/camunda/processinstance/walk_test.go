@@ -0,0 +1,140 @@
+package processinstance
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafvonb/camunder/pkg/camunda"
+	"github.com/stretchr/testify/require"
+)
+
+// walkAPI serves a process instance tree given as children by parent key.
+type walkAPI struct {
+	API
+	children map[int64][]int64
+	delay    time.Duration
+	fail     int64 // key whose children search fails
+
+	mu          sync.Mutex
+	running     int
+	maxRunning  int
+	searchCount int
+}
+
+func (a *walkAPI) GetProcessInstanceByKey(_ context.Context, key int64) (ProcessInstance, error) {
+	return ProcessInstance{Key: key}, nil
+}
+
+func (a *walkAPI) GetDirectChildrenOfProcessInstance(ctx context.Context, key int64) (ProcessInstances, error) {
+	a.mu.Lock()
+	a.running++
+	a.searchCount++
+	a.maxRunning = max(a.maxRunning, a.running)
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		a.running--
+		a.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(a.delay):
+	case <-ctx.Done():
+		return ProcessInstances{}, ctx.Err()
+	}
+	if key == a.fail {
+		return ProcessInstances{}, errors.New("search failed")
+	}
+	var r ProcessInstances
+	for _, k := range a.children[key] {
+		r.Items = append(r.Items, ProcessInstance{Key: k, ParentKey: key})
+	}
+	r.Total = int32(len(r.Items))
+	return r, nil
+}
+
+// wideTree has a root with 4 children, each with 3 children of its own.
+func wideTree() map[int64][]int64 {
+	return map[int64][]int64{
+		1:  {10, 20, 30, 40},
+		10: {11, 12, 13},
+		20: {21, 22, 23},
+		30: {31, 32, 33},
+		40: {41, 42, 43},
+	}
+}
+
+func TestWalkDescendants_Parallel(t *testing.T) {
+	api := &walkAPI{children: wideTree(), delay: 20 * time.Millisecond}
+	type visit struct {
+		key   int64
+		depth int
+	}
+	var visits []visit
+	desc, edges, chain, err := WalkDescendants(t.Context(), api, 1, WithWalkParallel(4),
+		WithOnFound(func(pi ProcessInstance, depth int) { visits = append(visits, visit{pi.Key, depth}) }))
+	require.NoError(t, err)
+
+	require.Len(t, desc, 17)
+	require.Len(t, chain, 17)
+	require.Equal(t, int64(1), desc[0])
+	require.ElementsMatch(t, []int64{10, 20, 30, 40}, edges[1])
+	require.ElementsMatch(t, []int64{21, 22, 23}, edges[20])
+	require.Contains(t, edges, int64(42), "leaves have an entry")
+	require.Nil(t, edges[42])
+	require.Equal(t, int64(20), chain[22].ParentKey)
+
+	require.Len(t, visits, 17, "each instance is streamed once")
+	require.Equal(t, visit{1, 0}, visits[0])
+	for _, v := range visits[1:] {
+		want := 1
+		if v.key%10 != 0 {
+			want = 2
+		}
+		require.Equal(t, want, v.depth, "depth of %d", v.key)
+	}
+	require.Equal(t, 17, api.searchCount)
+	require.Greater(t, api.maxRunning, 1, "children are searched in parallel")
+	require.LessOrEqual(t, api.maxRunning, 4)
+}
+
+func TestWalkDescendants_MaxDepth(t *testing.T) {
+	api := &walkAPI{children: wideTree()}
+	desc, edges, _, err := WalkDescendants(t.Context(), api, 1, WithMaxDepth(1))
+	require.NoError(t, err)
+	require.Len(t, desc, 5)
+	require.Nil(t, edges[10], "the children of the last level are not searched")
+	require.Equal(t, 1, api.searchCount)
+}
+
+func TestWalkDescendants_MaxNodes(t *testing.T) {
+	api := &walkAPI{children: wideTree()}
+	desc, edges, chain, err := WalkDescendants(t.Context(), api, 1, WithMaxNodes(3))
+	require.ErrorIs(t, err, ErrMaxNodesReached)
+	require.Equal(t, []int64{1, 10, 20}, desc, "the instances found so far are returned")
+	require.Equal(t, []int64{10, 20}, edges[1])
+	require.Len(t, chain, 3)
+}
+
+func TestWalkDescendants_CycleDetected(t *testing.T) {
+	api := &walkAPI{children: map[int64][]int64{1: {2}, 2: {3}, 3: {1}}}
+	_, _, _, err := WalkDescendants(t.Context(), api, 1)
+	require.ErrorIs(t, err, camunda.ErrCycleDetected)
+}
+
+func TestWalkDescendants_SearchError(t *testing.T) {
+	api := &walkAPI{children: wideTree(), fail: 30, delay: time.Millisecond}
+	_, _, _, err := WalkDescendants(t.Context(), api, 1, WithWalkParallel(2))
+	require.ErrorContains(t, err, "list children of 30: search failed")
+}
+
+func TestWalkDescendants_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	api := &walkAPI{children: wideTree(), delay: time.Second}
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, _, _, err := WalkDescendants(ctx, api, 1)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,63 @@
+package variable
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/grafvonb/camunder/pkg/camunda"
+)
+
+type API interface {
+	camunda.Base
+	GetVariableByKey(ctx context.Context, key int64) (Variable, error)
+	SearchVariables(ctx context.Context, filter SearchFilterOpts, size int32) (Variables, error)
+	SearchVariablesPage(ctx context.Context, filter SearchFilterOpts, size int32, after camunda.Cursor) (camunda.Page[Variable], error)
+	SetVariables(ctx context.Context, elementInstanceKey int64, variables map[string]any, local bool) (SetResponse, error)
+}
+
+// Variable is a process variable. Value holds the decoded JSON value; a truncated value
+// is not valid JSON and is kept as the raw string.
+type Variable struct {
+	Key                int64  `json:"key,omitempty"`
+	Name               string `json:"name,omitempty"`
+	Value              any    `json:"value"`
+	Truncated          bool   `json:"truncated,omitempty"`
+	ProcessInstanceKey int64  `json:"processInstanceKey,omitempty"`
+	ScopeKey           int64  `json:"scopeKey,omitempty"`
+	TenantId           string `json:"tenantId,omitempty"`
+}
+
+type Variables struct {
+	Total int32      `json:"total,omitempty"`
+	Items []Variable `json:"items,omitempty"`
+}
+
+type SetResponse struct {
+	StatusCode int
+	Status     string
+}
+
+// SearchFilterOpts selects variables; zero values are not used as filter.
+type SearchFilterOpts struct {
+	ProcessInstanceKey int64
+	ScopeKey           int64
+	Name               string
+}
+
+// DecodeValue decodes a variable value from the JSON string form used by the APIs.
+// Numbers are decoded as json.Number, so that large integers such as keys keep all their digits.
+// Values that are not valid JSON (e.g. truncated ones) are returned as they are.
+func DecodeValue(raw string) any {
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return raw
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return raw
+	}
+	return v
+}
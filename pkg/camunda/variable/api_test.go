@@ -0,0 +1,22 @@
+package variable_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grafvonb/camunder/pkg/camunda/variable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeValue(t *testing.T) {
+	require.Equal(t, "approved", variable.DecodeValue(`"approved"`))
+	require.Equal(t, json.Number("42"), variable.DecodeValue(`42`))
+	// keys and other large integers keep all their digits
+	require.Equal(t, json.Number("2251799813685251"), variable.DecodeValue(`2251799813685251`))
+	require.Equal(t, true, variable.DecodeValue(`true`))
+	require.Nil(t, variable.DecodeValue(`null`))
+	require.Equal(t, map[string]any{"id": "A-1", "items": []any{json.Number("1"), json.Number("2")}}, variable.DecodeValue(`{"id":"A-1","items":[1,2]}`))
+	require.Equal(t, `42 43`, variable.DecodeValue(`42 43`))
+	// truncated values are not valid JSON and come back unchanged
+	require.Equal(t, `{"id":"A-1","ite`, variable.DecodeValue(`{"id":"A-1","ite`))
+}